@@ -0,0 +1,233 @@
+package sprite
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Constant behaviours of the world bounds
+const (
+	// Nothing happens outside of the bounds
+	BoundsNone = iota
+
+	// Come back from the opposite edge
+	BoundsWrap
+
+	// Stop at the edge, the velocity toward the edge is lost
+	BoundsClamp
+
+	// Stop at the edge and reflect the velocity
+	BoundsBounce
+
+	// Hide the sprite when it is completely outside, a sprite of a pool returns to the pool
+	BoundsKill
+)
+
+//Rect is a rectangle (in pixel)
+type Rect struct {
+	Min, Max Point
+}
+
+//NewRect creates a rectangle from its position and its size
+func NewRect(x, y, width, height float64) Rect {
+	return Rect{Min: Point{X: x, Y: y}, Max: Point{X: x + width, Y: y + height}}
+}
+
+//Width returns the width of the rectangle
+func (r Rect) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+//Height returns the height of the rectangle
+func (r Rect) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+//Overlaps reports whether the two rectangles have a common area
+func (r Rect) Overlaps(other Rect) bool {
+	return r.Min.X < other.Max.X && other.Min.X < r.Max.X && r.Min.Y < other.Max.Y && other.Min.Y < r.Max.Y
+}
+
+//Contains reports whether the point is inside the rectangle
+func (r Rect) Contains(x, y float64) bool {
+	return x >= r.Min.X && x < r.Max.X && y >= r.Min.Y && y < r.Max.Y
+}
+
+//WorldBounds keeps a sprite, or the sprites of a group, inside a rectangle
+type WorldBounds struct {
+	// Rectangle of the world (in pixel)
+	Rect Rect
+
+	// Behaviour= BoundsNone, BoundsWrap, BoundsClamp, BoundsBounce, BoundsKill
+	Behaviour int
+
+	// function to launch when the sprite wraps, touches an edge or is killed
+	OnOutOfBounds func(*Sprite)
+}
+
+// corners of the current frame on the screen, in the order top-left, top-right, bottom-right, bottom-left
+func (sprite *Sprite) corners() [4]Point {
+	return sprite.transformRect(NewRect(0, 0, sprite.GetWidth(), sprite.GetHeight()))
+}
+
+// corners of a rectangle of the current frame once transformed on the screen
+func (sprite *Sprite) transformRect(r Rect) [4]Point {
+	g := sprite.geoM()
+
+	var c [4]Point
+	for i, p := range [4]Point{r.Min, {X: r.Max.X, Y: r.Min.Y}, r.Max, {X: r.Min.X, Y: r.Max.Y}} {
+		c[i].X, c[i].Y = g.Apply(p.X, p.Y)
+	}
+	return c
+}
+
+// corners of the current frame on the screen without the offset of the Shake effects, which must not move the sprite out of its bounds
+func (sprite *Sprite) steadyCorners() [4]Point {
+	var g ebiten.GeoM
+	x, y := sprite.Pivot()
+	g.Translate(-x, -y)
+	g.Concat(sprite.transformGeoM(false))
+
+	var c [4]Point
+	for i, p := range [4]Point{{}, {X: sprite.GetWidth()}, {X: sprite.GetWidth(), Y: sprite.GetHeight()}, {Y: sprite.GetHeight()}} {
+		c[i].X, c[i].Y = g.Apply(p.X, p.Y)
+	}
+	return c
+}
+
+// smallest rectangle containing the points
+func boundingRect(points []Point) Rect {
+	r := Rect{Min: Point{X: math.Inf(1), Y: math.Inf(1)}, Max: Point{X: math.Inf(-1), Y: math.Inf(-1)}}
+	for _, p := range points {
+		r.Min.X = math.Min(r.Min.X, p.X)
+		r.Min.Y = math.Min(r.Min.Y, p.Y)
+		r.Max.X = math.Max(r.Max.X, p.X)
+		r.Max.Y = math.Max(r.Max.Y, p.Y)
+	}
+	return r
+}
+
+// transformation of the position of the sprite to the world : its skew and the transformation of its parents
+func (sprite *Sprite) positionGeoM() ebiten.GeoM {
+	var g ebiten.GeoM
+	g.Skew(deg2rad(sprite.SkewX), deg2rad(sprite.SkewY))
+	if sprite.parent != nil {
+		g.Concat(sprite.parent.transformGeoM(false))
+	}
+	return g
+}
+
+// transform a vector (a move or a velocity) without the translation
+func applyVector(g *ebiten.GeoM, x, y float64) (float64, float64) {
+	x1, y1 := g.Apply(x, y)
+	x0, y0 := g.Apply(0, 0)
+	return x1 - x0, y1 - y0
+}
+
+// move the sprite by a vector of the world, g is its positionGeoM
+func (sprite *Sprite) moveInWorld(g *ebiten.GeoM, dx, dy float64) {
+	if dx == 0 && dy == 0 || !g.IsInvertible() {
+		return
+	}
+	inverse := *g
+	inverse.Invert()
+	dx, dy = applyVector(&inverse, dx, dy)
+	sprite.X += dx
+	sprite.Y += dy
+}
+
+func (sprite *Sprite) applyWorldBounds() {
+	if sprite.WorldBounds != nil {
+		sprite.keepInside(sprite.WorldBounds)
+	}
+}
+
+// apply the behaviour of the bounds, with the transformed frame of the sprite
+func (sprite *Sprite) keepInside(bounds *WorldBounds) {
+	if bounds.Behaviour == BoundsNone {
+		return
+	}
+
+	c := sprite.steadyCorners()
+	b := boundingRect(c[:])
+	r := bounds.Rect
+	triggered := false
+
+	// the corners are in the world : the move and the velocity are computed in the world, then brought back to the parent
+	var dx, dy float64
+	g := sprite.positionGeoM()
+	vx, vy := applyVector(&g, sprite.VelocityX, sprite.VelocityY)
+
+	switch bounds.Behaviour {
+	case BoundsWrap:
+		if b.Min.X > r.Max.X {
+			dx = -r.Width() - b.Width()
+		} else if b.Max.X < r.Min.X {
+			dx = r.Width() + b.Width()
+		}
+		if b.Min.Y > r.Max.Y {
+			dy = -r.Height() - b.Height()
+		} else if b.Max.Y < r.Min.Y {
+			dy = r.Height() + b.Height()
+		}
+		triggered = dx != 0 || dy != 0
+		sprite.moveInWorld(&g, dx, dy)
+
+	case BoundsClamp, BoundsBounce:
+		// the velocity toward the edge is reflected by a bounce and lost by a clamp
+		reflect := 0.0
+		if bounds.Behaviour == BoundsBounce {
+			reflect = -1
+		}
+		if b.Min.X < r.Min.X {
+			dx = r.Min.X - b.Min.X
+			triggered = true
+			if vx < 0 {
+				vx *= reflect
+			}
+		} else if b.Max.X > r.Max.X {
+			dx = r.Max.X - b.Max.X
+			triggered = true
+			if vx > 0 {
+				vx *= reflect
+			}
+		}
+		if b.Min.Y < r.Min.Y {
+			dy = r.Min.Y - b.Min.Y
+			triggered = true
+			if vy < 0 {
+				vy *= reflect
+			}
+		} else if b.Max.Y > r.Max.Y {
+			dy = r.Max.Y - b.Max.Y
+			triggered = true
+			if vy > 0 {
+				vy *= reflect
+			}
+		}
+		if triggered {
+			sprite.moveInWorld(&g, dx, dy)
+			if g.IsInvertible() {
+				g.Invert()
+				sprite.VelocityX, sprite.VelocityY = applyVector(&g, vx, vy)
+			}
+			sprite.syncSpeedDirection()
+		}
+
+	case BoundsKill:
+		if !b.Overlaps(r) {
+			sprite.Hide()
+			triggered = true
+		}
+	}
+
+	if triggered && bounds.OnOutOfBounds != nil {
+		bounds.OnOutOfBounds(sprite)
+	}
+
+	// killed and not brought back by the callback
+	if triggered && bounds.Behaviour == BoundsKill && !sprite.Visible {
+		sprite.releaseToPool()
+	}
+}
@@ -0,0 +1,109 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func TestBoundsBehaviours(t *testing.T) {
+	world := NewRect(0, 0, 100, 100)
+	tests := []struct {
+		name                 string
+		behaviour            int
+		x, y, vx, vy         float64
+		wantX, wantY         float64
+		wantVX, wantVY       float64
+		wantVisible, trigger bool
+	}{
+		{"inside", BoundsClamp, 50, 50, 10, 10, 50, 50, 10, 10, true, false},
+		{"wrap right", BoundsWrap, 105, 50, 10, 0, -5, 50, 10, 0, true, true},
+		{"wrap top", BoundsWrap, 50, -15, 0, -10, 50, 95, 0, -10, true, true},
+		{"clamp right", BoundsClamp, 95, 50, 10, 5, 90, 50, 0, 5, true, true},
+		{"clamp bottom", BoundsClamp, 50, 95, 10, 20, 50, 90, 10, 0, true, true},
+		{"clamp leaving", BoundsClamp, 95, 50, -10, 0, 90, 50, -10, 0, true, true},
+		{"bounce left", BoundsBounce, -5, 50, -10, 3, 0, 50, 10, 3, true, true},
+		{"kill", BoundsKill, 150, 50, 10, 0, 150, 50, 10, 0, false, true},
+		{"kill partly inside", BoundsKill, 95, 50, 10, 0, 95, 50, 10, 0, true, false},
+	}
+	for _, test := range tests {
+		s := newTestSprite(test.x, test.y, 10, 10)
+		s.VelocityX, s.VelocityY = test.vx, test.vy
+		triggered := false
+		s.WorldBounds = &WorldBounds{Rect: world, Behaviour: test.behaviour, OnOutOfBounds: func(*Sprite) { triggered = true }}
+		s.applyWorldBounds()
+
+		if !near(s.X, test.wantX) || !near(s.Y, test.wantY) || !near(s.VelocityX, test.wantVX) || !near(s.VelocityY, test.wantVY) {
+			t.Errorf("%s : position %v %v velocity %v %v", test.name, s.X, s.Y, s.VelocityX, s.VelocityY)
+		}
+		if s.Visible != test.wantVisible || triggered != test.trigger {
+			t.Errorf("%s : visible %v triggered %v", test.name, s.Visible, triggered)
+		}
+	}
+}
+
+func TestBoundsTransformed(t *testing.T) {
+	world := NewRect(0, 0, 100, 100)
+
+	// centered, zoomed and rotated : the frame covers 20x40 around the position after a quarter turn
+	s := newTestSprite(95, 50, 40, 20)
+	s.CenterCoordonnates = true
+	s.Angle = 90
+	s.WorldBounds = &WorldBounds{Rect: world, Behaviour: BoundsClamp}
+	s.applyWorldBounds()
+	assertNear(t, "rotated X", s.X, 90)
+
+	s = newTestSprite(5, 50, 10, 10)
+	s.AnchorX, s.AnchorY = 0.5, 0.5
+	s.Zoom(2)
+	s.WorldBounds = &WorldBounds{Rect: world, Behaviour: BoundsClamp}
+	s.applyWorldBounds()
+	assertNear(t, "zoomed X", s.X, 10)
+}
+
+func TestBoundsIgnoreShake(t *testing.T) {
+	s := newTestSprite(95, 50, 5, 5)
+	s.shakeX = 20 // the Shake effect moves the frame outside
+	s.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 100, 100), Behaviour: BoundsKill}
+	s.applyWorldBounds()
+	if !s.Visible {
+		t.Error("shaken sprite killed")
+	}
+
+	s.WorldBounds.Behaviour = BoundsWrap
+	s.applyWorldBounds()
+	assertNear(t, "shaken X", s.X, 95)
+}
+
+func TestClampWithGravity(t *testing.T) {
+	s := newTestSprite(50, 85, 10, 10)
+	s.Gravity = 1000
+	s.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 100, 100), Behaviour: BoundsClamp}
+
+	// resting on the floor
+	for i := 0; i < 60; i++ {
+		s.integrate(1.0 / 60)
+		s.applyWorldBounds()
+	}
+	assertNear(t, "resting Y", s.Y, 90)
+
+	// the velocity did not grow while clamped
+	s.WorldBounds = nil
+	s.integrate(1.0 / 60)
+	if s.Y-90 > 1 {
+		t.Errorf("released sprite moved %v pixels in one frame", s.Y-90)
+	}
+}
+
+func TestGroupBounds(t *testing.T) {
+	g := NewGroup()
+	g.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 100, 100), Behaviour: BoundsClamp}
+
+	free := newTestSprite(150, 50, 10, 10)
+	own := newTestSprite(150, 50, 10, 10)
+	own.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 200, 200), Behaviour: BoundsClamp}
+	g.Add(free, own)
+	g.Update()
+
+	if free.X > 90 || own.X != 150 {
+		t.Errorf("free sprite at %v, sprite with its own bounds at %v", free.X, own.X)
+	}
+}
@@ -0,0 +1,264 @@
+package sprite
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+//Camera converts the coordonnates of the world into coordonnates of the screen
+type Camera struct {
+	// Position of the center of the view in the world
+	X, Y float64
+
+	// Size of the view on the screen (in pixel)
+	Width, Height float64
+
+	// Zoom in or out (1 is unchanged)
+	Zoom float64
+
+	// Angle of rotation of the view in degres
+	Angle float64
+
+	// Sprite followed by the camera (nil is none)
+	Target *Sprite
+
+	// Size of the rectangle around the center of the view where the target moves without moving the camera (in pixel of the world)
+	DeadzoneWidth, DeadzoneHeight float64
+
+	// Time to cover most of the distance to the target (in second, 0 is immediate)
+	Smoothing float64
+
+	// Rectangle of the world the view cannot leave (nil is none), the rotation is not taken into account
+	Limits *Rect
+
+	// Intensity of the shake from 0 to 1, use AddTrauma
+	Trauma float64
+
+	// Trauma lost per second
+	TraumaDecay float64
+
+	// Offset (in pixel) and angle (in degres) of the shake when the trauma is 1
+	MaxShakeOffset, MaxShakeAngle float64
+
+	// Frequency of the shake (in hertz)
+	ShakeFrequency float64
+
+	// Skip the sprites outside of the surface
+	Culling bool
+
+	// Sprites drawn and culled by the last Draw
+	Stats DrawStats
+
+	// Offset and angle of the shake at the current frame
+	shakeX, shakeY, shakeAngle float64
+
+	// random phases of the shake
+	seedX, seedY, seedAngle float64
+
+	// Time of the last update and of the start of the shake
+	lastUpdate time.Time
+	shakeTime  float64
+
+	// Update was called since the last Draw
+	updated bool
+}
+
+/*
+NewCamera creates a camera for a view of the size of the screen
+
+Example :
+
+camera := sprite.NewCamera(windowWidth, windowHeight)
+camera.Follow(hero)
+camera.Limits = &sprite.Rect{Max: sprite.Point{X: 2000, Y: 1000}}
+*/
+func NewCamera(width, height float64) *Camera {
+	camera := new(Camera)
+	camera.Width = width
+	camera.Height = height
+	camera.X = width / 2
+	camera.Y = height / 2
+	camera.Zoom = 1
+	camera.TraumaDecay = 1
+	camera.MaxShakeOffset = 10
+	camera.MaxShakeAngle = 5
+	camera.ShakeFrequency = 15
+	camera.Culling = true
+	camera.seedX = rand.Float64() * 100
+	camera.seedY = rand.Float64() * 100
+	camera.seedAngle = rand.Float64() * 100
+	return camera
+}
+
+//Follow centers the view on a sprite at each update (nil stops following)
+func (camera *Camera) Follow(target *Sprite) {
+	camera.Target = target
+}
+
+//LookAt centers the view on a point of the world, within the limits
+func (camera *Camera) LookAt(x, y float64) {
+	camera.X = x
+	camera.Y = y
+	camera.applyLimits()
+}
+
+/*
+AddTrauma shakes the camera, the trauma is clamped between 0 and 1 and decreases over time
+
+Example :
+
+camera.AddTrauma(0.5) // explosion
+*/
+func (camera *Camera) AddTrauma(amount float64) {
+	camera.Trauma = math.Max(0, math.Min(1, camera.Trauma+amount))
+}
+
+//Update follows the target, keeps the view inside the limits and shakes the camera
+func (camera *Camera) Update() {
+	camera.updated = true
+
+	dt := deltaTime(&camera.lastUpdate)
+
+	if camera.Target != nil {
+		camera.follow(dt)
+	}
+	camera.applyLimits()
+	camera.shake(dt)
+}
+
+// move toward the target, out of the deadzone
+func (camera *Camera) follow(dt float64) {
+	g := camera.Target.nodeGeoM()
+	targetX, targetY := g.Apply(0, 0) // position of the target in the world
+
+	x, y := camera.X, camera.Y
+	if dx := targetX - x; math.Abs(dx) > camera.DeadzoneWidth/2 {
+		x = targetX - math.Copysign(camera.DeadzoneWidth/2, dx)
+	}
+	if dy := targetY - y; math.Abs(dy) > camera.DeadzoneHeight/2 {
+		y = targetY - math.Copysign(camera.DeadzoneHeight/2, dy)
+	}
+
+	// exponential smoothing, independent of the frame rate
+	where := 1.0
+	if camera.Smoothing > 0 {
+		where = 1 - math.Exp(-3*dt/camera.Smoothing)
+	}
+	camera.X += (x - camera.X) * where
+	camera.Y += (y - camera.Y) * where
+}
+
+// keep the view inside the limits, centered if the limits are smaller than the view
+func (camera *Camera) applyLimits() {
+	if camera.Limits == nil {
+		return
+	}
+	halfWidth := camera.Width / 2 / camera.Zoom
+	halfHeight := camera.Height / 2 / camera.Zoom
+	camera.X = limit(camera.X, camera.Limits.Min.X+halfWidth, camera.Limits.Max.X-halfWidth)
+	camera.Y = limit(camera.Y, camera.Limits.Min.Y+halfHeight, camera.Limits.Max.Y-halfHeight)
+}
+
+func limit(value, min, max float64) float64 {
+	if min > max {
+		return (min + max) / 2
+	}
+	return math.Max(min, math.Min(max, value))
+}
+
+// compute the offset of the shake from the trauma, then decrease the trauma
+func (camera *Camera) shake(dt float64) {
+	camera.shakeX, camera.shakeY, camera.shakeAngle = 0, 0, 0
+	if camera.Trauma <= 0 {
+		camera.shakeTime = 0
+		return
+	}
+
+	camera.shakeTime += dt
+	t := camera.shakeTime * camera.ShakeFrequency
+	intensity := camera.Trauma * camera.Trauma // smoother falloff
+	camera.shakeX = camera.MaxShakeOffset * intensity * noise(t, camera.seedX)
+	camera.shakeY = camera.MaxShakeOffset * intensity * noise(t, camera.seedY)
+	camera.shakeAngle = camera.MaxShakeAngle * intensity * noise(t, camera.seedAngle)
+
+	camera.Trauma = math.Max(0, camera.Trauma-camera.TraumaDecay*dt)
+}
+
+// smooth pseudo-random value between -1 and 1
+func noise(t, seed float64) float64 {
+	return (math.Sin(2*math.Pi*t+seed) + math.Sin(2*math.Pi*1.71*t+2*seed) + math.Sin(2*math.Pi*2.93*t+3*seed)) / 3
+}
+
+// transformation from the world to the screen
+func (camera *Camera) geoM() ebiten.GeoM {
+	var g ebiten.GeoM
+	g.Translate(-camera.X-camera.shakeX, -camera.Y-camera.shakeY)
+	g.Rotate(-deg2rad(camera.Angle + camera.shakeAngle))
+	g.Scale(camera.Zoom, camera.Zoom)
+	g.Translate(camera.Width/2, camera.Height/2)
+	return g
+}
+
+//WorldToScreen converts coordonnates of the world into coordonnates of the screen
+func (camera *Camera) WorldToScreen(x, y float64) (float64, float64) {
+	g := camera.geoM()
+	return g.Apply(x, y)
+}
+
+/*
+ScreenToWorld converts coordonnates of the screen into coordonnates of the world
+
+Example :
+
+x, y := ebiten.CursorPosition()
+worldX, worldY := camera.ScreenToWorld(float64(x), float64(y))
+*/
+func (camera *Camera) ScreenToWorld(x, y float64) (float64, float64) {
+	g := camera.geoM()
+	if !g.IsInvertible() { // zoom 0
+		return camera.X, camera.Y
+	}
+	g.Invert()
+	return g.Apply(x, y)
+}
+
+//Viewport returns the smallest rectangle of the world containing the view
+func (camera *Camera) Viewport() Rect {
+	var corners [4]Point
+	for i, p := range [4]Point{{}, {X: camera.Width}, {X: camera.Width, Y: camera.Height}, {Y: camera.Height}} {
+		corners[i].X, corners[i].Y = camera.ScreenToWorld(p.X, p.Y)
+	}
+	return boundingRect(corners[:])
+}
+
+/*
+Draw draws sprites and groups through the camera, after updating them and the camera if Update was not called since the last Draw
+
+The sprites outside of the surface are not drawn but they are still updated
+
+Example :
+
+camera.Draw(surface, background, enemies, hero)
+*/
+func (camera *Camera) Draw(surface *ebiten.Image, drawables ...Drawable) {
+	// the target moves before the camera follows it
+	for _, d := range drawables {
+		d.prepare()
+	}
+	if !camera.updated {
+		camera.Update()
+	}
+	camera.updated = false
+
+	camera.Stats = DrawStats{}
+	v := &view{geoM: camera.geoM(), stats: &camera.Stats, camera: camera}
+	if camera.Culling {
+		v.viewport = surfaceRect(surface)
+	}
+	for _, d := range drawables {
+		d.drawView(surface, v)
+	}
+}
@@ -0,0 +1,89 @@
+package sprite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCameraFollow(t *testing.T) {
+	tests := []struct {
+		name             string
+		deadzone         float64
+		smoothing, dt    float64
+		targetX, targetY float64
+		wantX, wantY     float64
+	}{
+		{"immediate", 0, 0, 0.1, 300, 200, 300, 200},
+		{"inside the deadzone", 100, 0, 0.1, 140, 80, 100, 100},
+		{"out of the deadzone", 100, 0, 0.1, 300, 20, 250, 70},
+		{"smoothed", 0, 1, 1.0 / 3, 200, 100, 100 + 100*(1-math.Exp(-1)), 100},
+		{"smoothed out of the deadzone", 100, 1, 1.0 / 3, 250, 100, 100 + 100*(1-math.Exp(-1)), 100},
+	}
+	for _, test := range tests {
+		camera := NewCamera(200, 200)
+		camera.DeadzoneWidth, camera.DeadzoneHeight = test.deadzone, test.deadzone
+		camera.Smoothing = test.smoothing
+		camera.Follow(newTestSprite(test.targetX, test.targetY, 10, 10))
+		camera.follow(test.dt)
+		assertNear(t, test.name+" x", camera.X, test.wantX)
+		assertNear(t, test.name+" y", camera.Y, test.wantY)
+	}
+}
+
+func TestCameraLimits(t *testing.T) {
+	camera := NewCamera(200, 100)
+	camera.Limits = &Rect{Max: Point{X: 1000, Y: 80}}
+
+	// the view stays inside, centered where the limits are smaller than the view
+	camera.LookAt(-50, 500)
+	assertNear(t, "left x", camera.X, 100)
+	assertNear(t, "small y", camera.Y, 40)
+	camera.LookAt(990, 0)
+	assertNear(t, "right x", camera.X, 900)
+
+	// zoomed in : the view is smaller
+	camera.Zoom = 2
+	camera.LookAt(990, 0)
+	assertNear(t, "zoomed right x", camera.X, 950)
+	assertNear(t, "zoomed y", camera.Y, 25)
+}
+
+func TestCameraScreenToWorld(t *testing.T) {
+	tests := []struct {
+		zoom, angle float64
+	}{
+		{1, 0},
+		{2, 0},
+		{0.5, 30},
+		{3, -90},
+	}
+	for _, test := range tests {
+		camera := NewCamera(320, 240)
+		camera.X, camera.Y = 500, 300
+		camera.Zoom, camera.Angle = test.zoom, test.angle
+
+		// the center of the view is on the center of the screen
+		x, y := camera.WorldToScreen(500, 300)
+		assertNear(t, "center x", x, 160)
+		assertNear(t, "center y", y, 120)
+
+		for _, p := range []Point{{X: 0, Y: 0}, {X: 320, Y: 17}, {X: 45, Y: 240}} {
+			wx, wy := camera.ScreenToWorld(p.X, p.Y)
+			x, y := camera.WorldToScreen(wx, wy)
+			assertNear(t, "round-trip x", x, p.X)
+			assertNear(t, "round-trip y", y, p.Y)
+
+			// the distance to the center is scaled by the zoom
+			d := math.Hypot(p.X-160, p.Y-120) / test.zoom
+			assertNear(t, "distance", math.Hypot(wx-500, wy-300), d)
+		}
+	}
+
+	// turned counter-clockwise : the world turns clockwise on the screen
+	camera := NewCamera(320, 240)
+	camera.X, camera.Y = 500, 300
+	camera.Angle = 90
+	x, y := camera.WorldToScreen(510, 300)
+	assertNear(t, "turned x", x, 160)
+	assertNear(t, "turned y", y, 130)
+}
@@ -0,0 +1,103 @@
+package sprite
+
+import (
+	"math"
+)
+
+// Constant collision modes
+const (
+	// Compare the axis-aligned bounding boxes
+	CollisionAABB = iota
+
+	// Compare the oriented boxes (separating axis), for rotated or skewed sprites
+	CollisionOBB
+
+	// Compare the solid pixels of the current steps (see CollidesPixel)
+	CollisionPixel
+
+	// Compare the hurtboxes of the current steps (see AddShape)
+	CollisionShapes
+)
+
+/*
+SetHitbox sets the rectangle used for collisions inside the steps of an animation (in pixel from the top-left corner of the step)
+
+Example :
+
+mySprite.SetHitbox("walk-right", 4, 8, 16, 24)
+*/
+func (sprite *Sprite) SetHitbox(label string, x, y, width, height float64) {
+	r := NewRect(x, y, width, height)
+	sprite.Animations[label].Hitbox = &r
+}
+
+// corners of the hitbox of the current animation once transformed on the screen
+func (sprite *Sprite) hitboxCorners() [4]Point {
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	if currentAnimation.Hitbox != nil {
+		return sprite.transformRect(*currentAnimation.Hitbox)
+	}
+	return sprite.corners()
+}
+
+//Bounds returns the axis-aligned bounding box of the hitbox once transformed (position, center, zoom, flip, rotation and skew)
+func (sprite *Sprite) Bounds() Rect {
+	c := sprite.hitboxCorners()
+	return boundingRect(c[:])
+}
+
+/*
+Overlaps reports whether the hitboxes of the two sprites overlap
+
+The pixels are compared if one of the sprites uses CollisionPixel, the hurtboxes if one of them uses CollisionShapes,
+the oriented boxes if one of them uses CollisionOBB, else the bounding boxes
+*/
+func (sprite *Sprite) Overlaps(other *Sprite) bool {
+	if sprite.Collision == CollisionPixel || other.Collision == CollisionPixel {
+		return sprite.CollidesPixel(other)
+	}
+	if sprite.Collision == CollisionShapes || other.Collision == CollisionShapes {
+		return sprite.HurtboxesOverlap(other)
+	}
+	if !sprite.Bounds().Overlaps(other.Bounds()) {
+		return false
+	}
+	if sprite.Collision == CollisionOBB || other.Collision == CollisionOBB {
+		a := sprite.hitboxCorners()
+		b := other.hitboxCorners()
+		return polygonsOverlap(a[:], b[:])
+	}
+	return true
+}
+
+// separating axis test between two convex polygons
+func polygonsOverlap(a, b []Point) bool {
+	for _, polygon := range [2][]Point{a, b} {
+		for i := range polygon {
+			p1 := polygon[i]
+			p2 := polygon[(i+1)%len(polygon)]
+			normal := Point{X: p1.Y - p2.Y, Y: p2.X - p1.X}
+			if normal.X == 0 && normal.Y == 0 { // degenerated edge
+				continue
+			}
+
+			minA, maxA := project(a, normal)
+			minB, maxB := project(b, normal)
+			if maxA <= minB || maxB <= minA { // separated on this axis
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// projection of a polygon on an axis
+func project(polygon []Point, axis Point) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, p := range polygon {
+		d := p.X*axis.X + p.Y*axis.Y
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return min, max
+}
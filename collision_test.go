@@ -0,0 +1,64 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func TestPolygonsOverlap(t *testing.T) {
+	square := []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	tests := []struct {
+		name  string
+		other []Point
+		want  bool
+	}{
+		{"inside", []Point{{X: 2, Y: 2}, {X: 8, Y: 2}, {X: 8, Y: 8}, {X: 2, Y: 8}}, true},
+		{"crossing", []Point{{X: 5, Y: 5}, {X: 15, Y: 5}, {X: 15, Y: 15}, {X: 5, Y: 15}}, true},
+		{"touching", []Point{{X: 10, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}}, false},
+		{"far", []Point{{X: 30, Y: 30}, {X: 40, Y: 30}, {X: 40, Y: 40}}, false},
+		// the bounding boxes overlap, the diamond is separated by the diagonal axis
+		{"diamond in the corner", []Point{{X: 14, Y: 8}, {X: 20, Y: 14}, {X: 14, Y: 20}, {X: 8, Y: 14}}, false},
+	}
+	for _, test := range tests {
+		if got := polygonsOverlap(square, test.other); got != test.want {
+			t.Errorf("%s : %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBoundsAndHitbox(t *testing.T) {
+	s := newTestSprite(10, 20, 30, 40)
+	if b := s.Bounds(); b != NewRect(10, 20, 30, 40) {
+		t.Errorf("Bounds = %v", b)
+	}
+
+	s.SetHitbox("default", 5, 5, 10, 10)
+	if b := s.Bounds(); b != NewRect(15, 25, 10, 10) {
+		t.Errorf("hitbox Bounds = %v", b)
+	}
+
+	// flipped around the anchor
+	s.AnchorX = 0.5
+	s.ZoomX = -1
+	b := s.Bounds()
+	assertNear(t, "flipped Min.X", b.Min.X, 10)
+	assertNear(t, "flipped Max.X", b.Max.X, 20)
+}
+
+func TestOverlapsAABBAndOBB(t *testing.T) {
+	a := newTestSprite(0, 0, 20, 20)
+	a.CenterCoordonnates = true
+	a.Angle = 45
+	b := newTestSprite(9, 9, 5, 5) // in the corner of the bounding box of a, outside of the rotated square
+
+	if !a.Overlaps(b) {
+		t.Error("bounding boxes do not overlap")
+	}
+	a.Collision = CollisionOBB
+	if a.Overlaps(b) {
+		t.Error("oriented boxes overlap")
+	}
+	b.X, b.Y = 2, 2
+	if !a.Overlaps(b) || !b.Overlaps(a) {
+		t.Error("oriented boxes do not overlap")
+	}
+}
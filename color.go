@@ -0,0 +1,50 @@
+package sprite
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"image/color"
+	"math"
+)
+
+// build the colour matrix of the sprite : multipliers, saturation, hue, contrast, brightness, inversion and tint
+// (the zero value of the colour properties other than the multipliers leaves the colours unchanged)
+func (sprite *Sprite) colorM() ebiten.ColorM {
+	var c ebiten.ColorM
+
+	// Hue and Alpha multipliers
+	c.Scale(sprite.Red, sprite.Green, sprite.Blue, sprite.Alpha)
+
+	// saturation and true hue rotation
+	if sprite.Saturation != 0 || sprite.HueRotation != 0 {
+		c.ChangeHSV(sprite.HueRotation*math.Pi/180, 1+sprite.Saturation, 1)
+	}
+
+	// contrast around the middle gray
+	if sprite.Contrast != 0 {
+		k := 1 + sprite.Contrast
+		c.Scale(k, k, k, 1)
+		offset := (1 - k) / 2
+		c.Translate(offset, offset, offset, 0)
+	}
+
+	if sprite.Brightness != 0 {
+		c.Translate(sprite.Brightness, sprite.Brightness, sprite.Brightness, 0)
+	}
+
+	// blend between the colour and its negative
+	if sprite.Inversion != 0 {
+		k := sprite.Inversion
+		c.Scale(1-2*k, 1-2*k, 1-2*k, 1)
+		c.Translate(k, k, k, 0)
+	}
+
+	// blend between the colour and the tint
+	if sprite.Tint != nil && sprite.TintStrength != 0 {
+		k := sprite.TintStrength
+		tint := color.NRGBAModel.Convert(sprite.Tint).(color.NRGBA)
+		c.Scale(1-k, 1-k, 1-k, 1)
+		c.Translate(float64(tint.R)/255*k, float64(tint.G)/255*k, float64(tint.B)/255*k, 0)
+	}
+
+	return c
+}
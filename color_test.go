@@ -0,0 +1,70 @@
+package sprite
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColorMNeutral(t *testing.T) {
+	s := &Sprite{Red: 1, Green: 1, Blue: 1, Alpha: 1} // not created by NewSprite
+	c := s.colorM()
+	in := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	if out := color.NRGBAModel.Convert(c.Apply(in)).(color.NRGBA); out != in {
+		t.Errorf("zero colour properties changed %v into %v", in, out)
+	}
+}
+
+func TestColorMEffects(t *testing.T) {
+	in := color.NRGBA{R: 200, G: 100, B: 50, A: 255}
+	tests := []struct {
+		name  string
+		setup func(s *Sprite)
+		check func(out color.NRGBA) bool
+	}{
+		{"grayscale", func(s *Sprite) { s.Saturation = -1 }, func(out color.NRGBA) bool {
+			return out.R == out.G && out.G == out.B
+		}},
+		{"no contrast", func(s *Sprite) { s.Contrast = -1 }, func(out color.NRGBA) bool {
+			return out.R >= 127 && out.R <= 128 && out.R == out.G && out.G == out.B
+		}},
+		{"inverted", func(s *Sprite) { s.Inversion = 1 }, func(out color.NRGBA) bool {
+			return out.R == 55 && out.G == 155 && out.B == 205
+		}},
+		{"white tint", func(s *Sprite) { s.Tint = color.White; s.TintStrength = 1 }, func(out color.NRGBA) bool {
+			return out.R == 255 && out.G == 255 && out.B == 255
+		}},
+		{"brighter", func(s *Sprite) { s.Brightness = 0.2 }, func(out color.NRGBA) bool {
+			return out.R > in.R && out.G > in.G && out.B > in.B
+		}},
+	}
+	for _, test := range tests {
+		s := NewSprite()
+		test.setup(s)
+		c := s.colorM()
+		out := color.NRGBAModel.Convert(c.Apply(in)).(color.NRGBA)
+		if !test.check(out) {
+			t.Errorf("%s : %v", test.name, out)
+		}
+	}
+}
+
+func TestColourEffectDefaultTargets(t *testing.T) {
+	tests := []struct {
+		effect int
+		want   float64
+	}{
+		{Tint, 1},
+		{Saturate, -1},
+		{Brighten, 1},
+		{Contrast, 1},
+		{Invert, 1},
+	}
+	for _, test := range tests {
+		s := newTestSprite(0, 0, 10, 10)
+		e := &animationEffect{options: &EffectOptions{Effect: test.effect}}
+		s.startEffect(e)
+		if e.valueStart != 0 || e.valueEnd != test.want {
+			t.Errorf("effect %d : from %v to %v, want from 0 to %v", test.effect, e.valueStart, e.valueEnd, test.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sprite
+
+import (
+	"github.com/hajimehoshi/ebiten"
+)
+
+//DrawStats counts the sprites drawn and skipped by a group or a camera, each particle counts as a sprite and each tile or parallax layer as one sprite
+type DrawStats struct {
+	// Sprites drawn on the surface
+	Drawn int
+
+	// Sprites skipped because they were outside of the viewport
+	Culled int
+}
+
+func (stats *DrawStats) add(other DrawStats) {
+	stats.Drawn += other.Drawn
+	stats.Culled += other.Culled
+}
+
+// rectangle covering the whole surface
+func surfaceRect(surface *ebiten.Image) *Rect {
+	w, h := surface.Size()
+	r := NewRect(0, 0, float64(w), float64(h))
+	return &r
+}
+
+// report whether a frame of this size transformed by g is inside the viewport, and count it
+func (v *view) visible(g *ebiten.GeoM, width, height float64) bool {
+	inside := true
+	if v.viewport != nil {
+		var corners [4]Point
+		for i, p := range [4]Point{{}, {X: width}, {X: width, Y: height}, {Y: height}} {
+			corners[i].X, corners[i].Y = g.Apply(p.X, p.Y)
+		}
+		inside = boundingRect(corners[:]).Overlaps(*v.viewport)
+	}
+
+	v.count(inside)
+	return inside
+}
+
+// count a drawable as drawn, or as culled when it is outside of the viewport
+func (v *view) count(inside bool) {
+	if v.stats != nil {
+		if inside {
+			v.stats.Drawn++
+		} else {
+			v.stats.Culled++
+		}
+	}
+}
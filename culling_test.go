@@ -0,0 +1,71 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// drawable counted like a sprite frame of this rectangle
+type testBox struct {
+	x, y, width, height float64
+}
+
+func (b *testBox) Update()                   {}
+func (b *testBox) prepare()                  {}
+func (b *testBox) order() (float64, float64) { return 0, b.y }
+
+func (b *testBox) drawView(surface *ebiten.Image, v *view) {
+	var g ebiten.GeoM
+	g.Translate(b.x, b.y)
+	g.Concat(v.geoM)
+	v.visible(&g, b.width, b.height)
+}
+
+func TestCullingStats(t *testing.T) {
+	viewport := NewRect(0, 0, 100, 100)
+	boxes := []Drawable{
+		&testBox{10, 10, 20, 20},   // inside
+		&testBox{-10, 50, 20, 20},  // across the left edge
+		&testBox{95, 95, 20, 20},   // across the bottom-right corner
+		&testBox{100, 10, 20, 20},  // touching the right edge : outside
+		&testBox{-30, -30, 20, 20}, // outside
+		&testBox{200, 200, 5, 5},   // outside
+	}
+
+	g := NewGroup()
+	g.AddDrawable(boxes...)
+	g.drawView(nil, &view{viewport: &viewport})
+	if g.Stats != (DrawStats{Drawn: 3, Culled: 3}) {
+		t.Errorf("stats %+v, want 3 drawn and 3 culled", g.Stats)
+	}
+
+	// the stats of a group are added to the stats of its parent
+	inner := NewGroup()
+	inner.AddDrawable(&testBox{50, 50, 5, 5}, &testBox{500, 50, 5, 5})
+	outer := NewGroup()
+	outer.AddDrawable(g, inner, &testBox{0, 0, 5, 5})
+	outer.drawView(nil, &view{viewport: &viewport})
+	if inner.Stats != (DrawStats{Drawn: 1, Culled: 1}) {
+		t.Errorf("inner stats %+v, want 1 drawn and 1 culled", inner.Stats)
+	}
+	if outer.Stats != (DrawStats{Drawn: 5, Culled: 4}) {
+		t.Errorf("outer stats %+v, want 5 drawn and 4 culled", outer.Stats)
+	}
+
+	// the view moves the boxes
+	var moved view
+	moved.viewport = &viewport
+	moved.geoM.Translate(100, 0)
+	g.drawView(nil, &moved)
+	if g.Stats != (DrawStats{Drawn: 1, Culled: 5}) {
+		t.Errorf("moved stats %+v, want 1 drawn and 5 culled", g.Stats)
+	}
+
+	// without culling : all drawn
+	g.Culling = false
+	g.drawView(nil, &view{viewport: &viewport})
+	if g.Stats != (DrawStats{Drawn: 6}) {
+		t.Errorf("stats without culling %+v, want 6 drawn", g.Stats)
+	}
+}
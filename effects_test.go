@@ -0,0 +1,219 @@
+package sprite
+
+import (
+	"testing"
+	"time"
+)
+
+// start an effect on a sprite without waiting for the game loop
+func startTestEffect(s *Sprite, options *EffectOptions) *animationEffect {
+	e := &animationEffect{options: options}
+	s.startEffect(e)
+	return e
+}
+
+func TestEffectTarget(t *testing.T) {
+	assertNear(t, "legacy", effectTarget(5, nil, nil, 3), 3)
+	assertNear(t, "To 0", effectTarget(5, Value(0), nil, 3), 0)
+	assertNear(t, "By -5", effectTarget(5, nil, Value(-5), 3), 0)
+	assertNear(t, "To before By", effectTarget(5, Value(1), Value(2), 3), 1)
+}
+
+func TestMoveTargets(t *testing.T) {
+	s := newTestSprite(40, 30, 10, 10)
+
+	e := startTestEffect(s, &EffectOptions{Effect: Move, ToX: Value(0), ByY: Value(10)})
+	assertNear(t, "xEnd", e.xEnd, 0)
+	assertNear(t, "yEnd", e.yEnd, 40)
+
+	// a zero legacy coordinate keeps the current one
+	e = startTestEffect(s, &EffectOptions{Effect: Move, X: 100})
+	assertNear(t, "legacy xEnd", e.xEnd, 100)
+	assertNear(t, "legacy yEnd", e.yEnd, 30)
+}
+
+func TestTurnTargets(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Angle = 30
+
+	// the legacy angle is absolute, the rotation starts from the current angle
+	e := startTestEffect(s, &EffectOptions{Effect: Turn, Angle: 90})
+	assertNear(t, "angleStart", e.angleStart, 30)
+	assertNear(t, "angleEnd", e.angleEnd, 90)
+
+	e = startTestEffect(s, &EffectOptions{Effect: Turn, Angle: 90, Clockwise: true})
+	assertNear(t, "clockwise angleEnd", e.angleEnd, -90)
+
+	e = startTestEffect(s, &EffectOptions{Effect: Turn, By: Value(45)})
+	assertNear(t, "By angleEnd", e.angleEnd, 75)
+
+	e = startTestEffect(s, &EffectOptions{Effect: Turn, To: Value(0)})
+	assertNear(t, "To angleEnd", e.angleEnd, 0)
+}
+
+func TestHueTargets(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Green = 0.5
+
+	// legacy channels : zero is unchanged, the options are not modified
+	options := &EffectOptions{Effect: Hue, Red: 0.2}
+	e := startTestEffect(s, options)
+	assertNear(t, "redEnd", e.redEnd, 0.2)
+	assertNear(t, "greenEnd", e.greenEnd, 1)
+	assertNear(t, "blueEnd", e.blueEnd, 1)
+	if options.Green != 0 {
+		t.Errorf("options.Green modified to %v", options.Green)
+	}
+
+	e = startTestEffect(s, &EffectOptions{Effect: Hue, To: Value(0)})
+	assertNear(t, "To redEnd", e.redEnd, 0)
+	assertNear(t, "To greenEnd", e.greenEnd, 0)
+
+	e = startTestEffect(s, &EffectOptions{Effect: Hue, By: Value(-0.5)})
+	assertNear(t, "By redEnd", e.redEnd, 0.5)
+	assertNear(t, "By greenEnd", e.greenEnd, 0)
+}
+
+// run an effect as if it started some time ago
+func applyTestEffect(s *Sprite, options *EffectOptions, elapsed time.Duration) *animationEffect {
+	s.AddEffect(options)
+	effects := s.Animations[options.Animation].Effects
+	e := effects[len(effects)-1]
+	e.timeStart = time.Now().Add(-elapsed)
+	e.timeEnd = e.timeStart.Add(options.durationTime)
+	s.applyEffects()
+	return e
+}
+
+func TestYoyoCount(t *testing.T) {
+	tests := []struct {
+		yoyo    int
+		elapsed time.Duration
+		from    float64
+		to      float64
+	}{
+		{0, 250 * time.Millisecond, 0.4, 0.55}, // one go and return : halfway to the target
+		{0, 500 * time.Millisecond, 0, 0.1},    // at the target
+		{2, 250 * time.Millisecond, 0, 0.1},    // two go and return : at the target
+		{2, 500 * time.Millisecond, 0.9, 1},    // back to the start
+		{4, 260 * time.Millisecond, 0.6, 0.95}, // start of the second go and return
+		{4, 440 * time.Millisecond, 0.5, 0.8},  // coming back
+	}
+	for _, test := range tests {
+		s := newTestSprite(0, 0, 10, 10)
+		applyTestEffect(s, &EffectOptions{Effect: Fade, To: Value(0), Duration: 1000, GoBack: true, YoyoCount: test.yoyo}, test.elapsed)
+		if s.Alpha < test.from || s.Alpha > test.to {
+			t.Errorf("YoyoCount %d after %v : Alpha = %v, want between %v and %v", test.yoyo, test.elapsed, s.Alpha, test.from, test.to)
+		}
+	}
+}
+
+func TestRepeatCount(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	var repeats []int
+	done := 0
+	options := &EffectOptions{Effect: Fade, To: Value(0), Duration: 100, Repeat: true, RepeatCount: 2,
+		OnRepeat: func(i int) { repeats = append(repeats, i) }, Callback: func() { done++ }}
+	applyTestEffect(s, options, time.Second) // end of the first run
+
+	for i := 0; i < 5 && len(s.Animations["default"].Effects) > 0; i++ {
+		e := s.Animations["default"].Effects[0]
+		e.timeStart = time.Now().Add(-time.Second)
+		e.timeEnd = e.timeStart.Add(options.durationTime)
+		s.applyEffects()
+	}
+	if len(repeats) != 2 || repeats[0] != 1 || repeats[1] != 2 || done != 3 {
+		t.Errorf("repeats %v, callbacks %d", repeats, done)
+	}
+	if len(s.Animations["default"].Effects) != 0 {
+		t.Error("effect not removed after its repetitions")
+	}
+}
+
+func TestFinishedEffectsRemoved(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	for i := 0; i < 10; i++ {
+		applyTestEffect(s, &EffectOptions{Effect: Fade, To: Value(0), Duration: 100}, time.Second)
+	}
+	running := &EffectOptions{Effect: Fade, To: Value(0), Duration: 1000, Repeat: true}
+	applyTestEffect(s, running, 0)
+	applyTestEffect(s, &EffectOptions{Effect: Fade, To: Value(0), Duration: 100}, time.Second)
+
+	effects := s.Animations["default"].Effects
+	if len(effects) != 1 || effects[0].options != running || running.index != 0 {
+		t.Errorf("%d effects left, want the running one at index 0", len(effects))
+	}
+}
+
+func TestRepeatCountWithoutRepeat(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	repeats := 0
+	options := &EffectOptions{Effect: Fade, To: Value(0), Duration: 100, RepeatCount: 3, OnRepeat: func(int) { repeats++ }}
+	applyTestEffect(s, options, time.Second)
+	for i := 0; i < 10 && len(s.Animations["default"].Effects) > 0; i++ {
+		e := s.Animations["default"].Effects[0]
+		e.timeStart = time.Now().Add(-time.Second)
+		e.timeEnd = e.timeStart.Add(options.durationTime)
+		s.applyEffects()
+	}
+	if repeats != 3 {
+		t.Errorf("%d repetitions, want 3", repeats)
+	}
+}
+
+// end an effect started by applyTestEffect, at its end or canceled
+func endTestEffect(s *Sprite, e *animationEffect, cancel bool) {
+	if cancel {
+		e.options.handle.Cancel()
+	} else {
+		e.timeStart = time.Now().Add(-time.Hour)
+		e.timeEnd = e.timeStart.Add(e.options.durationTime)
+	}
+	s.applyEffects()
+}
+
+func TestShakeBlinkPulseRestore(t *testing.T) {
+	for _, cancel := range []bool{false, true} {
+		s := newTestSprite(10, 20, 10, 10)
+		e := applyTestEffect(s, &EffectOptions{Effect: Shake, Amplitude: 5, Frequency: 30, Duration: 1000}, 100*time.Millisecond)
+		if s.shakeX == 0 && s.shakeY == 0 {
+			t.Error("no shake offset")
+		}
+		endTestEffect(s, e, cancel)
+		if s.X != 10 || s.Y != 20 || s.shakeX != 0 || s.shakeY != 0 {
+			t.Errorf("cancel %v : shake not restored, position %v,%v offset %v,%v", cancel, s.X, s.Y, s.shakeX, s.shakeY)
+		}
+
+		s = newTestSprite(0, 0, 10, 10)
+		e = applyTestEffect(s, &EffectOptions{Effect: Blink, Frequency: 1, Duration: 1000}, 600*time.Millisecond)
+		if !s.blinkHidden || !s.Visible {
+			t.Error("the blink does not hide the frame only")
+		}
+		endTestEffect(s, e, cancel)
+		if s.blinkHidden || !s.Visible {
+			t.Errorf("cancel %v : blink not restored", cancel)
+		}
+
+		s = newTestSprite(0, 0, 10, 10)
+		s.ZoomX, s.ZoomY = 2, 3
+		e = applyTestEffect(s, &EffectOptions{Effect: Pulse, Amplitude: 0.5, Frequency: 1, Duration: 1000}, 250*time.Millisecond)
+		if s.ZoomX < 2.4 || s.ZoomX > 2.6 {
+			t.Errorf("pulsed ZoomX = %v, want about 2.5", s.ZoomX)
+		}
+		endTestEffect(s, e, cancel)
+		if s.ZoomX != 2 || s.ZoomY != 3 {
+			t.Errorf("cancel %v : zoom not restored, %v %v", cancel, s.ZoomX, s.ZoomY)
+		}
+
+		s = newTestSprite(0, 0, 10, 10)
+		s.Alpha = 0.8
+		e = applyTestEffect(s, &EffectOptions{Effect: Pulse, PulseAlpha: true, Amplitude: 0.5, Frequency: 1, Duration: 1000}, 500*time.Millisecond)
+		if s.Alpha > 0.5 {
+			t.Errorf("pulsed Alpha = %v", s.Alpha)
+		}
+		endTestEffect(s, e, cancel)
+		if s.Alpha != 0.8 {
+			t.Errorf("cancel %v : alpha not restored, %v", cancel, s.Alpha)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/ryosama/go-sprite"
+	"log"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+	worldWidth   = windowWidth * 3
+	worldHeight  = windowHeight * 3
+	girlSpeed    = 120 // in pixel/second
+)
+
+var (
+	girl   *sprite.Sprite
+	world  = sprite.NewGroup()
+	camera = sprite.NewCamera(windowWidth, windowHeight)
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// draw the world through the camera
+	camera.Draw(surface, world)
+
+	// display some informations
+	x, y := ebiten.CursorPosition()
+	worldX, worldY := camera.ScreenToWorld(float64(x), float64(y))
+	ebitenutil.DebugPrint(surface, fmt.Sprintf("Arrows to walk, 'Space' to shake, 'Z'/'X' to zoom, 'R' to rotate\nMouse in the world X:%d Y:%d\nDrawn:%d Culled:%d", int(worldX), int(worldY), camera.Stats.Drawn, camera.Stats.Culled))
+
+	return nil
+}
+
+func main() {
+
+	// statues all over the world, sorted by Y to pass behind or in front of them
+	world.SortY = true
+	for x := 1; x < 6; x++ {
+		for y := 1; y < 6; y++ {
+			statue := sprite.NewSprite()
+			statue.AnchorX, statue.AnchorY = 0.5, 1 // feet of the statue
+			statue.AddAnimation("default", "gfx/som_girl_stand_down.png", 1, 1, ebiten.FilterDefault)
+			statue.Position(worldWidth/6*float64(x), worldHeight/6*float64(y))
+			statue.Saturation = -1
+			statue.Start()
+			world.Add(statue)
+		}
+	}
+
+	// create new sprite and load animations
+	girl = sprite.NewSprite()
+	girl.AnchorX, girl.AnchorY = 0.5, 1
+	girl.AddAnimation("stand-down", "gfx/som_girl_stand_down.png", 0, 1, ebiten.FilterDefault)
+	girl.AddAnimation("walk-right", "gfx/som_girl_walk_right.png", 700, 6, ebiten.FilterDefault)
+	girl.AddAnimation("walk-left", "gfx/som_girl_walk_left.png", 700, 6, ebiten.FilterDefault)
+	girl.AddAnimation("walk-up", "gfx/som_girl_walk_up.png", 500, 4, ebiten.FilterDefault)
+	girl.AddAnimation("walk-down", "gfx/som_girl_walk_down.png", 500, 4, ebiten.FilterDefault)
+	girl.Position(worldWidth/2, worldHeight/2)
+	girl.WorldBounds = &sprite.WorldBounds{Rect: sprite.NewRect(0, 0, worldWidth, worldHeight), Behaviour: sprite.BoundsClamp}
+	girl.CurrentAnimation = "stand-down"
+	girl.Start()
+	world.Add(girl)
+
+	// follow the girl without scrolling outside of the world
+	camera.Follow(girl)
+	camera.DeadzoneWidth, camera.DeadzoneHeight = 40, 30
+	camera.Smoothing = 0.3
+	camera.Limits = &sprite.Rect{Max: sprite.Point{X: worldWidth, Y: worldHeight}}
+	camera.LookAt(girl.X, girl.Y)
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	animation := "stand-down"
+	girl.VelocityX, girl.VelocityY = 0, 0
+
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		girl.VelocityX = -girlSpeed
+		animation = "walk-left"
+	} else if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		girl.VelocityX = girlSpeed
+		animation = "walk-right"
+	}
+
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		girl.VelocityY = -girlSpeed
+		animation = "walk-up"
+	} else if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		girl.VelocityY = girlSpeed
+		animation = "walk-down"
+	}
+
+	if animation != girl.CurrentAnimation {
+		girl.CurrentAnimation = animation
+		girl.Start()
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		camera.AddTrauma(0.6)
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyZ) {
+		camera.Zoom *= 1.02
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyX) {
+		camera.Zoom /= 1.02
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyR) {
+		camera.Angle++
+	}
+}
@@ -13,7 +13,7 @@ const (
 )
 
 var (
-	sprites [8]*sprite.Sprite
+	sprites [9]*sprite.Sprite
 )
 
 // update at every frame
@@ -85,7 +85,7 @@ func main() {
 	i++
 
 	// Move Relative X and Relative Y
-	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Move, X: sprites[i].X + 10, Y: sprites[i].Y + 10, Duration: 1000, Repeat: true, GoBack: true})
+	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Move, ByX: sprite.Value(10), ByY: sprite.Value(10), Duration: 1000, Repeat: true, GoBack: true})
 	i++
 
 	// multiple effects : Zoom->x3, HUE->Yellow, TURN->360°, MOVE-> x+100
@@ -95,6 +95,12 @@ func main() {
 	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Move, X: sprites[i].X + 100, Duration: 2000, Repeat: true, GoBack: true})
 	i++
 
+	// hit effects : Shake + Blink + Pulse
+	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Shake, Amplitude: 3, Frequency: 30, Decay: 1, Duration: 1000, Repeat: true})
+	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Blink, Frequency: 8, Duration: 1000, Repeat: true})
+	sprites[i].AddEffect(&sprite.EffectOptions{Effect: sprite.Pulse, Amplitude: 0.2, Frequency: 2, Duration: 1000, Repeat: true})
+	i++
+
 	// infinite loop
 	if err := ebiten.Run(update, windowWidth, windowWidth, scale, "Sprite demo"); err != nil {
 		log.Fatal(err)
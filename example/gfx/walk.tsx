@@ -0,0 +1,22 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" tiledversion="1.10.2" name="walk" tilewidth="30" tileheight="34" tilecount="6" columns="6">
+ <image source="som_girl_walk_right.png" width="180" height="34"/>
+ <tile id="0">
+  <animation>
+   <frame tileid="0" duration="120"/>
+   <frame tileid="1" duration="120"/>
+   <frame tileid="2" duration="120"/>
+   <frame tileid="3" duration="120"/>
+   <frame tileid="4" duration="120"/>
+   <frame tileid="5" duration="120"/>
+  </animation>
+ </tile>
+ <tile id="3">
+  <properties>
+   <property name="flags" type="int" value="2"/>
+  </properties>
+  <objectgroup draworder="index" id="2">
+   <object id="1" x="8" y="4" width="14" height="30"/>
+  </objectgroup>
+ </tile>
+</tileset>
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/ryosama/go-sprite"
+	"log"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+	girlSpeed    = 120 // in pixel/second
+)
+
+var (
+	girl   *sprite.Sprite
+	far    = sprite.NewParallaxLayer(0.2, 0.2) // far away, moves slowly
+	near   = sprite.NewParallaxLayer(0.6, 1)   // closer, moves faster
+	camera = sprite.NewCamera(windowWidth, windowHeight)
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// draw the layers from the farthest to the nearest
+	camera.Draw(surface, far, near, girl)
+
+	ebitenutil.DebugPrint(surface, "Left and Right to walk")
+
+	return nil
+}
+
+func main() {
+
+	// repeated everywhere and scrolling by itself, like clouds
+	far.AddAnimation("default", "gfx/som_girl_stand_down.png", 0, 1, ebiten.FilterDefault)
+	far.RepeatX, far.RepeatY = true, true
+	far.SpeedX = -15
+	far.Saturation = -1
+	far.Alpha = 0.4
+	far.Start()
+
+	// an animated crowd repeated on one line
+	near.AddAnimation("default", "gfx/som_girl_walk_right.png", 700, 6, ebiten.FilterDefault)
+	near.RepeatX = true
+	near.Position(0, windowHeight/2-40)
+	near.Brightness = -0.4
+	near.Start()
+
+	girl = sprite.NewSprite()
+	girl.CenterCoordonnates = true
+	girl.AddAnimation("stand-right", "gfx/som_girl_stand_right.png", 0, 1, ebiten.FilterDefault)
+	girl.AddAnimation("walk-right", "gfx/som_girl_walk_right.png", 700, 6, ebiten.FilterDefault)
+	girl.AddAnimation("stand-left", "gfx/som_girl_stand_left.png", 0, 1, ebiten.FilterDefault)
+	girl.AddAnimation("walk-left", "gfx/som_girl_walk_left.png", 700, 6, ebiten.FilterDefault)
+	girl.Position(windowWidth/2, windowHeight/2)
+	girl.CurrentAnimation = "stand-right"
+	girl.Start()
+
+	camera.Follow(girl)
+	camera.Smoothing = 0.2
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	animation := "stand-right"
+	if girl.CurrentAnimation == "walk-left" || girl.CurrentAnimation == "stand-left" {
+		animation = "stand-left"
+	}
+	girl.VelocityX = 0
+
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		girl.VelocityX = -girlSpeed
+		animation = "walk-left"
+	} else if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		girl.VelocityX = girlSpeed
+		animation = "walk-right"
+	}
+
+	if animation != girl.CurrentAnimation {
+		girl.CurrentAnimation = animation
+		girl.Start()
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/ryosama/go-sprite"
+	"log"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+)
+
+var (
+	fountain, explosions *sprite.Emitter
+	girl                 *sprite.Sprite
+	scene                = sprite.NewGroup()
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// draw the emitters and the girl by increasing Z
+	scene.Draw(surface)
+
+	ebitenutil.DebugPrint(surface, fmt.Sprintf("Click to explode, 'Space' to start/stop the fountain\nParticles:%d", fountain.Count()+explosions.Count()))
+
+	return nil
+}
+
+func main() {
+
+	// continuous fountain behind the girl
+	fountain = sprite.NewEmitter("gfx/explosion1.png", 500, 5, ebiten.FilterDefault)
+	fountain.Position(windowWidth/2, windowHeight-20)
+	fountain.Z = -1
+	fountain.Rate = 60
+	fountain.Lifetime = sprite.Range{Min: 1.5, Max: 2.5}
+	fountain.Speed = sprite.Range{Min: 120, Max: 180}
+	fountain.Direction = sprite.Range{Min: 80, Max: 100}
+	fountain.Spin = sprite.Range{Min: -180, Max: 180}
+	fountain.Scale = sprite.Range{Min: 0.3, Max: 0.6}
+	fountain.Gravity = 150
+	fountain.SpreadX = 10
+	fountain.AlphaCurve = sprite.Curve{{Life: 0.7, Value: 1}, {Life: 1, Value: 0}}
+	fountain.BlueCurve = sprite.Curve{{Life: 0, Value: 1}, {Life: 1, Value: 0.2}}
+	fountain.Start()
+
+	// bursts in front of the girl
+	explosions = sprite.NewEmitter("gfx/explosion3.png", 500, 9, ebiten.FilterDefault)
+	explosions.Z = 1
+	explosions.MaxParticles = 512
+	explosions.Lifetime = sprite.Range{Min: 0.4, Max: 0.8}
+	explosions.Speed = sprite.Range{Min: 20, Max: 100}
+	explosions.Direction = sprite.Range{Min: 0, Max: 360}
+	explosions.Scale = sprite.Range{Min: 0.2, Max: 0.5}
+	explosions.AnimateOverLife = true
+	explosions.ScaleCurve = sprite.Curve{{Life: 0, Value: 0.5, Easing: sprite.EaseOut}, {Life: 1, Value: 1.5}}
+
+	girl = sprite.NewSprite()
+	girl.AddAnimation("default", "gfx/som_girl_stand_down.png", 0, 1, ebiten.FilterDefault)
+	girl.AnchorX, girl.AnchorY = 0.5, 1
+	girl.Position(windowWidth/2, windowHeight-20)
+	girl.Start()
+
+	scene.AddDrawable(fountain, girl, explosions)
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		explosions.Position(float64(x), float64(y))
+		explosions.Burst(40)
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		if fountain.Emitting() {
+			fountain.Stop()
+		} else {
+			fountain.Start()
+		}
+	}
+}
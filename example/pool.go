@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/hajimehoshi/ebiten/inpututil"
+	"github.com/ryosama/go-sprite"
+	"log"
+	"math/rand"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+	bulletSpeed  = 200 // in pixel/second
+)
+
+var (
+	explosions, bullets *sprite.Pool
+	scene               = sprite.NewGroup()
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// draw the sprites in use
+	scene.Draw(surface)
+
+	ebitenutil.DebugPrint(surface, fmt.Sprintf("Click to explode, 'Space' to shoot\nExplosions size:%d in use:%d misses:%d\nBullets size:%d in use:%d misses:%d",
+		explosions.Stats.Size, explosions.Stats.InUse, explosions.Stats.Misses,
+		bullets.Stats.Size, bullets.Stats.InUse, bullets.Stats.Misses))
+
+	return nil
+}
+
+func main() {
+
+	// the images are loaded once by the templates
+	explosion := sprite.NewSprite()
+	explosion.AddAnimation("default", "gfx/explosion3.png", 500, 9, ebiten.FilterDefault)
+	explosion.CenterCoordonnates = true
+	explosions = sprite.NewPool(explosion, 10)
+	explosions.Group = scene
+
+	// the bullets return to the pool when they leave the window
+	bullet := sprite.NewSprite()
+	bullet.AddAnimation("default", "gfx/explosion1.png", 300, 5, ebiten.FilterDefault)
+	bullet.CenterCoordonnates = true
+	bullet.Zoom(0.5)
+	bullet.WorldBounds = &sprite.WorldBounds{Rect: sprite.NewRect(0, 0, windowWidth, windowHeight), Behaviour: sprite.BoundsKill}
+	bullets = sprite.NewPool(bullet, 20)
+	bullets.Group = scene
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		x, y := ebiten.CursorPosition()
+		s := explosions.Acquire()
+		s.Position(float64(x), float64(y))
+		s.RunOnce(nil) // back to the pool at the end of the animation
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
+		s := bullets.Acquire()
+		s.Position(0, rand.Float64()*windowHeight)
+		s.VelocityX = bulletSpeed
+		s.Start()
+	}
+}
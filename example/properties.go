@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/hajimehoshi/ebiten"
 	"github.com/ryosama/go-sprite"
+	"image/color"
 	"log"
 )
 
@@ -13,7 +14,8 @@ const (
 )
 
 var (
-	sprites [7]*sprite.Sprite
+	sprites [9]*sprite.Sprite
+	group   = sprite.NewGroup()
 )
 
 // update at every frame
@@ -25,9 +27,7 @@ func update(surface *ebiten.Image) error {
 	}
 
 	// draw sprites
-	for i := 0; i < len(sprites); i++ {
-		sprites[i].Draw(surface)
-	}
+	group.Draw(surface)
 
 	return nil
 }
@@ -42,6 +42,7 @@ func main() {
 		sprites[i].AddAnimation("default", "gfx/som_girl_stand_down.png", 1, 1, ebiten.FilterDefault)
 		sprites[i].Position(windowWidth/4*x, windowHeight/4*y)
 		sprites[i].Start()
+		group.Add(sprites[i])
 
 		x++
 		if x > 3 {
@@ -53,6 +54,7 @@ func main() {
 	i := 0
 
 	sprites[i].Zoom(2, 1.5) // set the multiplier ZoomX and ZoomY
+	sprites[i].Z = 1        // drawn over the others
 	i++
 
 	sprites[i].Skew(30, 10) // set SkewX and SkewY in degres
@@ -74,6 +76,13 @@ func main() {
 	sprites[i].Borders = true // Debug Borders
 	i++
 
+	sprites[i].Saturation = -1 // Grayscale
+	i++
+
+	sprites[i].Tint = color.White // Flash in white
+	sprites[i].TintStrength = 0.8
+	i++
+
 	// infinite loop
 	if err := ebiten.Run(update, windowWidth, windowWidth, scale, "Sprite demo"); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/ryosama/go-sprite"
+	"log"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+	scrollSpeed  = 3   // in pixel/frame
+)
+
+var (
+	level  *sprite.Map
+	camera = sprite.NewCamera(windowWidth, windowHeight)
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// draw all the layers of the map
+	camera.Draw(surface, level.Layers...)
+
+	// the leader walks on the tiles
+	leader := level.ObjectLayer("walkers").Objects[0]
+	x, y := ebiten.CursorPosition()
+	worldX, worldY := camera.ScreenToWorld(float64(x), float64(y))
+	ebitenutil.DebugPrint(surface, fmt.Sprintf("%s - arrows to scroll\n%s is a %s walking at %s\nFlags under the mouse:%d",
+		level.Properties["title"],
+		leader.Name, leader.Type, leader.Properties["speed"],
+		level.TileLayer("ground").FlagsAt(worldX, worldY),
+	))
+
+	return nil
+}
+
+func main() {
+
+	// tile layers, tile objects and collision shapes in one call
+	var err error
+	level, err = sprite.LoadMap("gfx/level.tmx", ebiten.FilterDefault)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	leader := level.ObjectLayer("walkers").Objects[0].Sprite
+	leader.VelocityX = 20
+	leader.WorldBounds = &sprite.WorldBounds{Rect: sprite.NewRect(0, 0, level.PixelWidth(), level.PixelHeight()), Behaviour: sprite.BoundsWrap}
+
+	camera.Limits = &sprite.Rect{Max: sprite.Point{X: level.PixelWidth(), Y: level.PixelHeight()}}
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		camera.X -= scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		camera.X += scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		camera.Y -= scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		camera.Y += scrollSpeed
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+	"github.com/ryosama/go-sprite"
+	"log"
+)
+
+const (
+	windowWidth  = 320 // Width of the window
+	windowHeight = 240 // Height of the window
+	scale        = 2   // Scale of the window
+	columns      = 60  // Size of the map (in tile)
+	rows         = 40
+	scrollSpeed  = 4 // in pixel/frame
+)
+
+var (
+	tileset *sprite.Tileset
+	ground  *sprite.TileLayer
+	camera  = sprite.NewCamera(windowWidth, windowHeight)
+)
+
+// update at every frame
+func update(surface *ebiten.Image) error {
+
+	// manage controle
+	binding()
+
+	// frame skip
+	if ebiten.IsDrawingSkipped() {
+		return nil
+	}
+
+	// only the tiles inside the screen are drawn
+	camera.Draw(surface, ground)
+
+	x, y := ebiten.CursorPosition()
+	worldX, worldY := camera.ScreenToWorld(float64(x), float64(y))
+	column, row := ground.CellAt(worldX, worldY)
+	ebitenutil.DebugPrint(surface, fmt.Sprintf("Arrows to scroll\nCell %d,%d Flags:%d", column, row, ground.FlagsAt(worldX, worldY)))
+
+	return nil
+}
+
+func main() {
+
+	// each step of the walk is a tile, the first one is animated with the whole walk
+	tileset = sprite.NewTileset("gfx/som_girl_walk_right.png", 30, 34, ebiten.FilterDefault)
+	tileset.AddAnimatedTile(0, 700, 0, 1, 2, 3, 4, 5)
+	tileset.SetFlags(1, 3) // the 4th tile is solid
+
+	ground = sprite.NewTileLayer(tileset, columns, rows)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			t := tileset.Tile((column + row) % tileset.Count)
+			if row%2 == 1 {
+				t |= sprite.TileFlipX // walk to the left
+			}
+			if column%7 == 0 {
+				t |= sprite.TileFlipY // upside down
+			}
+			ground.SetTile(column, row, t)
+		}
+	}
+
+	camera.Limits = &sprite.Rect{Max: sprite.Point{X: columns * 30, Y: rows * 34}}
+
+	// infinite loop
+	if err := ebiten.Run(update, windowWidth, windowHeight, scale, "Sprite demo"); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func binding() {
+	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+		camera.X -= scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyRight) {
+		camera.X += scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+		camera.Y -= scrollSpeed
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) {
+		camera.Y += scrollSpeed
+	}
+}
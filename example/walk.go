@@ -26,20 +26,6 @@ func update(surface *ebiten.Image) error {
 	// manage controle
 	binding()
 
-	// reset position if outside of the screen
-	if girl.X > windowWidth {
-		girl.X = 0 - girl.GetWidth()
-	}
-	if girl.X+girl.GetWidth() < 0 {
-		girl.X = windowWidth
-	}
-	if girl.Y+girl.GetHeight() < 0 {
-		girl.Y = windowHeight + 2*girl.GetHeight()
-	}
-	if girl.Y-2*girl.GetHeight() > windowHeight {
-		girl.Y = 0 - girl.GetHeight()
-	}
-
 	// frame skip
 	if ebiten.IsDrawingSkipped() {
 		return nil
@@ -69,6 +55,7 @@ func main() {
 
 	// set position and first animation
 	girl.Position(windowWidth/2, windowHeight/2)
+	girl.WorldBounds = &sprite.WorldBounds{Rect: sprite.NewRect(0, 0, windowWidth, windowHeight), Behaviour: sprite.BoundsWrap} // come back if outside of the screen
 	girl.CurrentAnimation = "stand-right"
 	girl.Start()
 
@@ -0,0 +1,360 @@
+package sprite
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+//Group owns sprites and other drawables (emitters, groups...), updates them together and draws them in z-order
+type Group struct {
+	// Order of drawing inside a parent group
+	Z float64
+
+	// Draw the sprites with the same Z from the top to the bottom of the screen (for top-down games)
+	SortY bool
+
+	// Visibility of the group
+	Visible bool
+
+	// Paused group : its sprites are drawn but not updated
+	Paused bool
+
+	// Colors multipliers applied to all the sprites
+	Red, Green, Blue float64
+
+	// Transparency applied to all the sprites
+	Alpha float64
+
+	// Skip the sprites outside of the surface
+	Culling bool
+
+	// Part of the movement of the camera followed by the sprites (0 is fixed on the screen, 1 moves with the world)
+	ScrollX, ScrollY float64
+
+	// Rectangle and behaviour to keep the sprites inside the world, for the sprites without bounds of their own (nil is none)
+	WorldBounds *WorldBounds
+
+	// Sprites drawn and culled by the last Draw
+	Stats DrawStats
+
+	// members in the order they were added
+	drawables []Drawable
+	members   map[Drawable]bool
+
+	// members sorted for drawing
+	sorted []Drawable
+
+	// Add and Remove called while iterating, applied at the end of the iteration
+	iterating int
+	changes   []groupChange
+}
+
+type groupChange struct {
+	drawable Drawable
+	add      bool
+}
+
+//Drawable is something drawn by a group or a camera : a sprite, a group, an emitter, a parallax layer or a tile layer
+type Drawable interface {
+	// Update at each frame
+	Update()
+
+	// update if Update was not called since the last draw
+	prepare()
+
+	// draw transformed by a view
+	drawView(surface *ebiten.Image, v *view)
+
+	// Z and Y to sort the drawables of a group
+	order() (float64, float64)
+}
+
+// transformation and colours applied when drawing a sprite
+type view struct {
+	geoM   ebiten.GeoM
+	colorM ebiten.ColorM
+
+	// rectangle of the surface where the sprites are visible (nil is no culling)
+	viewport *Rect
+
+	// counters of drawn and culled sprites (nil is none)
+	stats *DrawStats
+
+	// camera drawing the view (nil is none)
+	camera *Camera
+
+	// hidden view : nothing is drawn, the frame only ends
+	hidden bool
+}
+
+//NewGroup creates a new visible group
+func NewGroup() *Group {
+	g := new(Group)
+	g.Visible = true
+	g.Red = 1
+	g.Green = 1
+	g.Blue = 1
+	g.Alpha = 1
+	g.Culling = true
+	g.ScrollX = 1
+	g.ScrollY = 1
+	g.members = make(map[Drawable]bool)
+	return g
+}
+
+/*
+Add adds sprites to the group, it can be called while iterating over the group (from a callback)
+
+Example :
+
+enemies := sprite.NewGroup()
+enemies.Add(bat, ghost)
+*/
+func (g *Group) Add(sprites ...*Sprite) {
+	for _, s := range sprites {
+		g.AddDrawable(s)
+	}
+}
+
+//Remove removes sprites from the group, it can be called while iterating over the group (from a callback)
+func (g *Group) Remove(sprites ...*Sprite) {
+	for _, s := range sprites {
+		g.RemoveDrawable(s)
+	}
+}
+
+/*
+AddDrawable adds sprites, groups, emitters, parallax layers or tile layers to the group, it can be called while iterating over the group (from a callback)
+
+Example :
+
+scene := sprite.NewGroup()
+scene.AddDrawable(background, enemies, sparks)
+*/
+func (g *Group) AddDrawable(drawables ...Drawable) {
+	for _, d := range drawables {
+		if g.iterating > 0 {
+			g.changes = append(g.changes, groupChange{drawable: d, add: true})
+			continue
+		}
+		if !g.members[d] {
+			g.members[d] = true
+			g.drawables = append(g.drawables, d)
+		}
+	}
+}
+
+//RemoveDrawable removes sprites or other drawables from the group, it can be called while iterating over the group (from a callback)
+func (g *Group) RemoveDrawable(drawables ...Drawable) {
+	for _, d := range drawables {
+		if g.iterating > 0 {
+			g.changes = append(g.changes, groupChange{drawable: d})
+			continue
+		}
+		if !g.members[d] {
+			continue
+		}
+		delete(g.members, d)
+		for i, member := range g.drawables {
+			if member == d {
+				g.drawables = append(g.drawables[:i], g.drawables[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+//Clear removes all the members of the group
+func (g *Group) Clear() {
+	if g.iterating > 0 {
+		for _, d := range g.drawables {
+			g.changes = append(g.changes, groupChange{drawable: d})
+		}
+		return
+	}
+	g.drawables = nil
+	g.members = make(map[Drawable]bool)
+}
+
+//Contains returns true if the sprite or the drawable belongs to the group
+func (g *Group) Contains(d Drawable) bool {
+	return g.members[d]
+}
+
+//Len returns the number of members of the group
+func (g *Group) Len() int {
+	return len(g.drawables)
+}
+
+//Sprites returns the sprites of the group in the order they were added
+func (g *Group) Sprites() []*Sprite {
+	var sprites []*Sprite
+	for _, d := range g.drawables {
+		if s, ok := d.(*Sprite); ok {
+			sprites = append(sprites, s)
+		}
+	}
+	return sprites
+}
+
+//Drawables returns all the members of the group in the order they were added
+func (g *Group) Drawables() []Drawable {
+	return append([]Drawable(nil), g.drawables...)
+}
+
+/*
+Each calls a function for each sprite of the group, in the order they were added
+
+The groups, emitters and layers added by AddDrawable are skipped, use Drawables to get them
+
+Example :
+
+enemies.Each(func(s *sprite.Sprite) {
+	if s.Overlaps(hero) {
+		enemies.Remove(s)
+	}
+})
+*/
+func (g *Group) Each(f func(*Sprite)) {
+	g.each(func(d Drawable) {
+		if s, ok := d.(*Sprite); ok {
+			f(s)
+		}
+	})
+}
+
+// call a function for each member of the group
+func (g *Group) each(f func(Drawable)) {
+	g.iterating++
+	for _, d := range g.drawables {
+		f(d)
+	}
+	g.iterating--
+	g.applyChanges()
+}
+
+// apply Add and Remove called while iterating
+func (g *Group) applyChanges() {
+	if g.iterating > 0 || len(g.changes) == 0 {
+		return
+	}
+	changes := g.changes
+	g.changes = nil
+	for _, c := range changes {
+		if c.add {
+			g.AddDrawable(c.drawable)
+		} else {
+			g.RemoveDrawable(c.drawable)
+		}
+	}
+}
+
+//Hide the group
+func (g *Group) Hide() {
+	g.Visible = false
+}
+
+//Show the group
+func (g *Group) Show() {
+	g.Visible = true
+}
+
+//Pause the group, its sprites are no longer updated
+func (g *Group) Pause() {
+	g.Paused = true
+}
+
+//Resume the group
+func (g *Group) Resume() {
+	g.Paused = false
+}
+
+//Update updates all the members of the group, unless the group is paused
+func (g *Group) Update() {
+	if g.Paused {
+		return
+	}
+	g.each(g.update)
+}
+
+// update a member, then keep it inside the bounds of the group
+func (g *Group) update(d Drawable) {
+	d.Update()
+	if s, ok := d.(*Sprite); ok && g.WorldBounds != nil && s.WorldBounds == nil && s.shown() {
+		s.keepInside(g.WorldBounds)
+	}
+}
+
+func (g *Group) order() (float64, float64) {
+	return g.Z, 0
+}
+
+//Draw draws the sprites of the group by increasing Z, after updating those which were not updated since the last Draw
+func (g *Group) Draw(surface *ebiten.Image) {
+	g.prepare()
+	v := &view{}
+	if g.Culling {
+		v.viewport = surfaceRect(surface)
+	}
+	g.drawView(surface, v)
+}
+
+// update the sprites which were not updated since the last Draw
+func (g *Group) prepare() {
+	if !g.Paused {
+		g.each(func(d Drawable) {
+			if s, ok := d.(*Sprite); ok && !s.updated {
+				g.update(s)
+			} else {
+				d.prepare()
+			}
+		})
+	}
+}
+
+// draw the sprites by increasing Z, transformed by a view
+func (g *Group) drawView(surface *ebiten.Image, v *view) {
+	g.Stats = DrawStats{}
+	if !g.Visible || v.hidden {
+		hidden := &view{hidden: true}
+		for _, d := range g.drawables {
+			d.drawView(surface, hidden)
+		}
+		return
+	}
+
+	groupView := &view{geoM: v.geoM, viewport: v.viewport, stats: &g.Stats, camera: v.camera}
+	if v.camera != nil && (g.ScrollX != 1 || g.ScrollY != 1) {
+		groupView.geoM.Reset()
+		groupView.geoM.Translate((1-g.ScrollX)*v.camera.X, (1-g.ScrollY)*v.camera.Y)
+		groupView.geoM.Concat(v.geoM)
+	}
+	if !g.Culling {
+		groupView.viewport = nil
+	}
+	groupView.colorM.Scale(g.Red, g.Green, g.Blue, g.Alpha)
+	groupView.colorM.Concat(v.colorM)
+
+	// stable sort : the sprites with the same Z are drawn in the order they were added
+	g.sorted = append(g.sorted[:0], g.drawables...)
+	sort.SliceStable(g.sorted, func(i, j int) bool {
+		zi, yi := g.sorted[i].order()
+		zj, yj := g.sorted[j].order()
+		if zi != zj {
+			return zi < zj
+		}
+		return g.SortY && yi < yj
+	})
+
+	g.iterating++
+	for _, d := range g.sorted {
+		d.drawView(surface, groupView)
+	}
+	g.iterating--
+	g.applyChanges()
+
+	if v.stats != nil {
+		v.stats.add(g.Stats)
+	}
+}
@@ -0,0 +1,134 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// drawable recording its updates and draws
+type testDrawable struct {
+	name    string
+	z, y    float64
+	updates int
+	drawn   *[]string
+	colorM  ebiten.ColorM
+}
+
+func (d *testDrawable) Update()                   { d.updates++ }
+func (d *testDrawable) prepare()                  {}
+func (d *testDrawable) order() (float64, float64) { return d.z, d.y }
+
+func (d *testDrawable) drawView(surface *ebiten.Image, v *view) {
+	if !v.hidden {
+		*d.drawn = append(*d.drawn, d.name)
+		d.colorM = v.colorM
+	}
+}
+
+func TestGroupMembers(t *testing.T) {
+	g := NewGroup()
+	a := newTestSprite(0, 0, 10, 10)
+	b := newTestSprite(0, 0, 10, 10)
+	inner := NewGroup()
+	g.Add(a)
+	g.AddDrawable(inner, b)
+	g.Add(a) // once only
+
+	if g.Len() != 3 || !g.Contains(inner) || !g.Contains(b) {
+		t.Fatalf("%d members", g.Len())
+	}
+	var each []*Sprite
+	g.Each(func(s *Sprite) { each = append(each, s) })
+	if len(each) != 2 || each[0] != a || each[1] != b {
+		t.Error("Each does not skip the other drawables")
+	}
+
+	// removed while iterating : applied at the end
+	g.Each(func(s *Sprite) {
+		g.Remove(s)
+		if !g.Contains(s) {
+			t.Error("removed while iterating")
+		}
+	})
+	g.RemoveDrawable(inner)
+	if g.Len() != 0 {
+		t.Errorf("%d members left", g.Len())
+	}
+}
+
+func TestGroupOrder(t *testing.T) {
+	var drawn []string
+	g := NewGroup()
+	g.AddDrawable(
+		&testDrawable{name: "front", z: 1, y: 0, drawn: &drawn},
+		&testDrawable{name: "low", z: 0, y: 50, drawn: &drawn},
+		&testDrawable{name: "high", z: 0, y: 10, drawn: &drawn},
+		&testDrawable{name: "back", z: -1, y: 90, drawn: &drawn},
+	)
+
+	tests := []struct {
+		sortY bool
+		want  []string
+	}{
+		{false, []string{"back", "low", "high", "front"}}, // same Z : in the order they were added
+		{true, []string{"back", "high", "low", "front"}},  // same Z : from the top to the bottom
+	}
+	for _, test := range tests {
+		drawn = nil
+		g.SortY = test.sortY
+		g.drawView(nil, &view{})
+		if len(drawn) != len(test.want) {
+			t.Fatalf("SortY %v : drawn %v, want %v", test.sortY, drawn, test.want)
+		}
+		for i := range test.want {
+			if drawn[i] != test.want[i] {
+				t.Errorf("SortY %v : drawn %v, want %v", test.sortY, drawn, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestGroupPaused(t *testing.T) {
+	var drawn []string
+	d := &testDrawable{name: "d", drawn: &drawn}
+	g := NewGroup()
+	g.AddDrawable(d)
+
+	g.Pause()
+	g.Update()
+	g.prepare()
+	g.drawView(nil, &view{})
+	if d.updates != 0 || len(drawn) != 1 {
+		t.Errorf("paused : %d updates and %d draws, want 0 and 1", d.updates, len(drawn))
+	}
+
+	g.Resume()
+	g.Update()
+	if d.updates != 1 {
+		t.Errorf("resumed : %d updates, want 1", d.updates)
+	}
+}
+
+func TestGroupColour(t *testing.T) {
+	var drawn []string
+	d := &testDrawable{name: "d", drawn: &drawn}
+	inner := NewGroup()
+	inner.Red, inner.Alpha = 0.5, 0.8
+	inner.AddDrawable(d)
+	outer := NewGroup()
+	outer.Red, outer.Green = 0.5, 0.25
+	outer.AddDrawable(inner)
+	outer.drawView(nil, &view{})
+
+	// the sprite colour is multiplied by the colours of its groups, as the sprites draw
+	s := newTestSprite(0, 0, 10, 10)
+	s.Red, s.Blue = 0.5, 0.4
+	c := s.worldColorM()
+	c.Concat(d.colorM)
+	assertNear(t, "red", c.Element(0, 0), 0.125)
+	assertNear(t, "green", c.Element(1, 1), 0.25)
+	assertNear(t, "blue", c.Element(2, 2), 0.4)
+	assertNear(t, "alpha", c.Element(3, 3), 0.8)
+}
@@ -0,0 +1,213 @@
+package sprite
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+//Handle follows the completion of an effect, a RunOnce animation or a queued animation
+type Handle struct {
+	done     chan struct{}
+	once     sync.Once
+	canceled int32
+}
+
+type queuedAnimation struct {
+	label  string
+	handle *Handle
+}
+
+func newHandle() *Handle {
+	h := new(Handle)
+	h.done = make(chan struct{})
+	return h
+}
+
+/*
+Done returns a channel closed when the effect or the animation is over or canceled
+
+Example :
+
+<-mySprite.Effect(ctx, &sprite.EffectOptions{Effect: sprite.Fade, FadeFrom: 1, FadeTo: 0, Duration: 1000}).Done()
+*/
+func (h *Handle) Done() <-chan struct{} {
+	return h.done
+}
+
+//Cancel stops the effect or the animation at the next frame, it can be called from any goroutine
+func (h *Handle) Cancel() {
+	atomic.StoreInt32(&h.canceled, 1)
+}
+
+func (h *Handle) isCanceled() bool {
+	return atomic.LoadInt32(&h.canceled) == 1
+}
+
+func (h *Handle) close() {
+	h.once.Do(func() { close(h.done) })
+}
+
+// cancel the handle when the context is done, the goroutine ends when the handle is closed
+func (h *Handle) watch(ctx context.Context) {
+	if ctx.Done() == nil { // never canceled
+		return
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			h.Cancel()
+		case <-h.done:
+		}
+	}()
+}
+
+/*
+Effect adds an effect to the sprite from any goroutine. The effect is added by the game loop at the next frame
+
+Canceling the context cancels the effect
+
+Example :
+
+<-mySprite.Effect(ctx, &sprite.EffectOptions{Effect: sprite.Move, ToX: sprite.Value(0), Duration: 1000}).Done()
+*/
+func (sprite *Sprite) Effect(ctx context.Context, options *EffectOptions) *Handle {
+	h := newHandle()
+	h.watch(ctx)
+	sprite.enqueue(func() { sprite.addEffect(options, h) })
+	return h
+}
+
+/*
+RunOnceContext starts the animation only one time from any goroutine, like RunOnce
+
+Canceling the context stops and hides the sprite without calling the callback
+*/
+func (sprite *Sprite) RunOnceContext(ctx context.Context, c func(*Sprite)) *Handle {
+	h := newHandle()
+	h.watch(ctx)
+	sprite.enqueue(func() { sprite.runOnce(c, h) })
+	return h
+}
+
+/*
+QueueAnimation plays an animation once after the current one, it can be called from any goroutine
+
+Queued animations are played in order, the last one keeps looping when it is over.
+An unknown animation is skipped, its handle is canceled
+
+Example :
+
+mySprite.QueueAnimation(ctx, "attack-right")
+<-mySprite.QueueAnimation(ctx, "stand-right").Done()
+*/
+func (sprite *Sprite) QueueAnimation(ctx context.Context, label string) *Handle {
+	h := newHandle()
+	h.watch(ctx)
+	sprite.enqueue(func() {
+		sprite.queue = append(sprite.queue, &queuedAnimation{label: label, handle: h})
+	})
+	return h
+}
+
+// register a function to call from the game loop
+func (sprite *Sprite) enqueue(f func()) {
+	sprite.mutex.Lock()
+	sprite.pending = append(sprite.pending, f)
+	sprite.mutex.Unlock()
+}
+
+// call the functions registered from other goroutines and apply cancellations, from the game loop
+func (sprite *Sprite) processPending() {
+	sprite.mutex.Lock()
+	pending := sprite.pending
+	sprite.pending = nil
+	sprite.mutex.Unlock()
+
+	for _, f := range pending {
+		f()
+	}
+
+	// canceled RunOnce
+	if currentAnimation, ok := sprite.Animations[sprite.CurrentAnimation]; ok {
+		if h := currentAnimation.runOnceHandle; h != nil && h.isCanceled() {
+			currentAnimation.runOnceHandle = nil
+			sprite.Stop()
+			sprite.Hide()
+			h.close()
+			sprite.dropQueue() // the animations waiting for the end of the RunOnce are not played
+			sprite.releaseToPool()
+		}
+	}
+
+	// canceled effects of the animations not played, applyEffects ends those of the current animation
+	for label, animation := range sprite.Animations {
+		if label == sprite.CurrentAnimation {
+			continue
+		}
+		for _, e := range animation.Effects {
+			if e != nil && !e.finished && e.options.handle != nil && e.options.handle.isCanceled() {
+				if e.started {
+					sprite.endEffect(e)
+				}
+				e.finished = true
+				e.options.handle.close()
+			}
+		}
+		animation.compactEffects()
+	}
+
+	// canceled animations waiting in the queue
+	queue := sprite.queue[:0]
+	for _, q := range sprite.queue {
+		if q.handle.isCanceled() {
+			q.handle.close()
+		} else {
+			queue = append(queue, q)
+		}
+	}
+	sprite.queue = queue
+
+	// canceled animation currently played from the queue
+	if sprite.playing != nil && sprite.playing.handle.isCanceled() {
+		sprite.playing.handle.close()
+		sprite.playing = nil
+	}
+}
+
+// close the handles of the queued animations and of the one played from the queue, and empty the queue
+func (sprite *Sprite) dropQueue() {
+	for _, q := range sprite.queue {
+		q.handle.close()
+	}
+	sprite.queue = nil
+	if sprite.playing != nil {
+		sprite.playing.handle.close()
+		sprite.playing = nil
+	}
+}
+
+// go to the next animation of the queue at the end of the current one, return false if the queue is empty
+func (sprite *Sprite) nextQueuedAnimation() bool {
+	if sprite.playing != nil { // the queued animation is over
+		sprite.playing.handle.close()
+		sprite.playing = nil
+	}
+
+	for len(sprite.queue) > 0 {
+		q := sprite.queue[0]
+		sprite.queue[0] = nil
+		sprite.queue = sprite.queue[1:]
+		if _, ok := sprite.Animations[q.label]; !ok { // unknown animation : canceled and skipped
+			q.handle.Cancel()
+			q.handle.close()
+			continue
+		}
+
+		sprite.playing = q
+		sprite.CurrentAnimation = q.label
+		sprite.Reset()
+		return true
+	}
+	return false
+}
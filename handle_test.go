@@ -0,0 +1,156 @@
+package sprite
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// report whether the handle is done
+func isDone(h *Handle) bool {
+	select {
+	case <-h.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// update the sprite until the handle is done or a second has passed
+func updateUntilDone(s *Sprite, h *Handle) bool {
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); {
+		s.Update()
+		if isDone(h) {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+func TestReuseEffectOptions(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	options := &EffectOptions{Effect: Fade, To: Value(0), Duration: 10}
+
+	first := s.AddEffect(options)
+	if !updateUntilDone(s, first) {
+		t.Fatal("first effect not done")
+	}
+
+	second := s.AddEffect(options)
+	if second == first || isDone(second) {
+		t.Fatal("the reused options returned a handle already done")
+	}
+	// the finished first run is removed from the stack
+	if effects := s.Animations["default"].Effects; len(effects) != 1 || effects[0].finished {
+		t.Errorf("%d effects in the stack, want the second run only", len(effects))
+	}
+	if !updateUntilDone(s, second) {
+		t.Error("second effect not done")
+	}
+}
+
+func TestEffectHandleClosed(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	if h := s.AddEffect(&EffectOptions{Effect: Fade, Animation: "missing"}); !isDone(h) {
+		t.Error("effect on a missing animation not done")
+	}
+	if h := s.AddEffect(&EffectOptions{Effect: 1000}); !isDone(h) {
+		t.Error("unknown effect not done")
+	}
+}
+
+func TestCancelEffectOfAnotherAnimation(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Animations["walk"] = &Animation{Steps: 1, StepWidth: 10, StepHeight: 10}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := s.Effect(ctx, &EffectOptions{Effect: Fade, To: Value(0), Duration: 1000, Animation: "walk"})
+	s.Update()
+	if isDone(h) {
+		t.Fatal("effect done before being canceled")
+	}
+
+	cancel()
+	if !updateUntilDone(s, h) {
+		t.Error("canceled effect of an animation not played is not done")
+	}
+}
+
+func TestQueueAfterRunOnce(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Animations["default"].Steps = 2
+	s.Animations["walk"] = &Animation{Steps: 2, StepWidth: 10, StepHeight: 10, OneStepDuration: time.Hour}
+
+	called := false
+	h := s.RunOnce(func(*Sprite) { called = true })
+	queued := s.QueueAnimation(context.Background(), "walk")
+
+	if !updateUntilDone(s, h) {
+		t.Fatal("RunOnce not done")
+	}
+	if !called || s.CurrentAnimation != "walk" || !s.Visible || !s.Animated {
+		t.Errorf("after RunOnce : callback %v, animation %q, visible %v, animated %v", called, s.CurrentAnimation, s.Visible, s.Animated)
+	}
+	if isDone(queued) {
+		t.Error("queued animation done before being played")
+	}
+}
+
+func TestQueueUnknownAnimation(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Animations["default"].Steps = 2
+	s.Animations["walk"] = &Animation{Steps: 2, StepWidth: 10, StepHeight: 10, OneStepDuration: time.Hour}
+
+	typo := s.QueueAnimation(context.Background(), "wlak")
+	walk := s.QueueAnimation(context.Background(), "walk")
+	if !updateUntilDone(s, typo) {
+		t.Fatal("the unknown queued animation is not done")
+	}
+	if !typo.isCanceled() {
+		t.Error("the unknown queued animation is not canceled")
+	}
+	if s.CurrentAnimation != "walk" || isDone(walk) {
+		t.Errorf("animation %q after skipping the unknown one", s.CurrentAnimation)
+	}
+	s.Update() // no panic on the next steps
+}
+
+func TestCanceledRunOnceDropsQueue(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	s.Animations["default"].OneStepDuration = time.Hour
+	s.Animations["walk"] = &Animation{Steps: 1, StepWidth: 10, StepHeight: 10}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := s.RunOnceContext(ctx, nil)
+	queued := s.QueueAnimation(context.Background(), "walk")
+	s.Update()
+	cancel()
+	if !updateUntilDone(s, h) {
+		t.Fatal("canceled RunOnce not done")
+	}
+	if !updateUntilDone(s, queued) {
+		t.Error("the animation queued after a canceled RunOnce is not done")
+	}
+}
+
+func TestWatchEndsWithHandle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	before := runtime.NumGoroutine()
+	handles := make([]*Handle, 50)
+	for i := range handles {
+		handles[i] = newHandle()
+		handles[i].watch(ctx)
+	}
+	for _, h := range handles {
+		h.close()
+	}
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("%d goroutines left watching closed handles", n-before)
+	}
+}
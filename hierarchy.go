@@ -0,0 +1,109 @@
+package sprite
+
+import (
+	"errors"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+/*
+AddChild attaches a sprite to this one. Its position, zoom, angle, skew, colors and alpha become relative to this sprite
+
+The child is updated and drawn with its parent, do not draw it yourself. It is hidden while its parent is hidden.
+An error is returned, and nothing is attached, if the child is this sprite or one of its parents
+
+Example :
+
+body.AddChild(hat)
+hat.Position(0, -12) // 12 pixels above the body
+*/
+func (sprite *Sprite) AddChild(child *Sprite) error {
+	for s := sprite; s != nil; s = s.parent {
+		if s == child {
+			return errors.New("sprite: a sprite cannot be attached to itself or to one of its children")
+		}
+	}
+
+	if child.parent != nil {
+		child.parent.RemoveChild(child)
+	}
+	child.parent = sprite
+	sprite.children = append(sprite.children, child)
+	return nil
+}
+
+//RemoveChild detaches a child sprite, its properties become absolute again
+func (sprite *Sprite) RemoveChild(child *Sprite) {
+	for i, c := range sprite.children {
+		if c == child {
+			sprite.children = append(sprite.children[:i], sprite.children[i+1:]...)
+			child.parent = nil
+			return
+		}
+	}
+}
+
+// the sprite and its parents are visible
+func (sprite *Sprite) shown() bool {
+	for s := sprite; s != nil; s = s.parent {
+		if !s.Visible {
+			return false
+		}
+	}
+	return true
+}
+
+// the sprite and its parents are animated
+func (sprite *Sprite) running() bool {
+	for s := sprite; s != nil; s = s.parent {
+		if !s.Animated {
+			return false
+		}
+	}
+	return true
+}
+
+//Parent returns the sprite this sprite is attached to, nil if none
+func (sprite *Sprite) Parent() *Sprite {
+	return sprite.parent
+}
+
+//Children returns the sprites attached to this sprite
+func (sprite *Sprite) Children() []*Sprite {
+	return sprite.children
+}
+
+// transformation of the sprite and its parents : zoom, rotation, position and skew
+func (sprite *Sprite) nodeGeoM() ebiten.GeoM {
+	return sprite.transformGeoM(true)
+}
+
+// transformation of the sprite and its parents, with or without the offset of the Shake effects
+func (sprite *Sprite) transformGeoM(shake bool) ebiten.GeoM {
+	x, y := sprite.X, sprite.Y
+	if shake {
+		x += sprite.shakeX
+		y += sprite.shakeY
+	}
+
+	var g ebiten.GeoM
+	g.Scale(sprite.ZoomX, sprite.ZoomY)
+	g.Rotate(deg2rad(sprite.Angle))
+	g.Translate(x, y)
+
+	g.Skew(deg2rad(sprite.SkewX), deg2rad(sprite.SkewY))
+
+	if sprite.parent != nil {
+		g.Concat(sprite.parent.transformGeoM(shake))
+	}
+	return g
+}
+
+// colour matrix of the sprite and its parents
+func (sprite *Sprite) worldColorM() ebiten.ColorM {
+	c := sprite.colorM()
+	if sprite.parent != nil {
+		c.Concat(sprite.parent.worldColorM())
+	}
+	return c
+}
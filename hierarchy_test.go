@@ -0,0 +1,132 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func TestChildTransform(t *testing.T) {
+	body := newTestSprite(100, 100, 20, 20)
+	hat := newTestSprite(10, 0, 4, 4)
+	if err := body.AddChild(hat); err != nil {
+		t.Fatal(err)
+	}
+	body.Zoom(2)
+
+	c := hat.corners()
+	assertNear(t, "x", c[0].X, 120)
+	assertNear(t, "y", c[0].Y, 100)
+	assertNear(t, "width", c[1].X-c[0].X, 8)
+
+	// turned counter-clockwise : the hat goes up
+	body.Angle = 90
+	c = hat.corners()
+	assertNear(t, "turned x", c[0].X, 100)
+	assertNear(t, "turned y", c[0].Y, 80)
+
+	body.RemoveChild(hat)
+	if hat.Parent() != nil || len(body.Children()) != 0 {
+		t.Error("the child is still attached")
+	}
+	c = hat.corners()
+	assertNear(t, "detached x", c[0].X, 10)
+}
+
+func TestAddChildCycle(t *testing.T) {
+	a := newTestSprite(0, 0, 10, 10)
+	b := newTestSprite(0, 0, 10, 10)
+	c := newTestSprite(0, 0, 10, 10)
+	if a.AddChild(a) == nil {
+		t.Error("a sprite is attached to itself")
+	}
+	if err := a.AddChild(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddChild(c); err != nil {
+		t.Fatal(err)
+	}
+	if c.AddChild(a) == nil {
+		t.Error("a sprite is attached to its grandchild")
+	}
+	if a.Parent() != nil || c.Parent() != b {
+		t.Error("the refused call changed the hierarchy")
+	}
+
+	// moving a child to another parent
+	if err := a.AddChild(c); err != nil || c.Parent() != a || len(b.Children()) != 0 {
+		t.Error("the child is not moved")
+	}
+}
+
+func TestChildVisibility(t *testing.T) {
+	body := newTestSprite(0, 0, 10, 10)
+	hat := newTestSprite(0, 0, 4, 4)
+	body.AddChild(hat)
+
+	hat.Hide()
+	body.Hide()
+	if body.shown() || hat.shown() {
+		t.Error("hidden sprites are shown")
+	}
+	body.Show()
+	if !body.shown() || hat.shown() {
+		t.Error("showing the parent shows the hidden child")
+	}
+
+	hat.Show()
+	body.Hide()
+	if hat.shown() || !hat.Visible {
+		t.Error("the child is not hidden with its parent, or loses its own visibility")
+	}
+}
+
+func TestChildPause(t *testing.T) {
+	body := newTestSprite(0, 0, 10, 10)
+	hat := newTestSprite(0, 0, 4, 4)
+	body.AddChild(hat)
+	body.Resume()
+	hat.Resume()
+
+	hat.Pause()
+	body.Pause()
+	body.Resume()
+	if !body.running() || hat.running() || hat.Animated {
+		t.Error("resuming the parent resumes the paused child")
+	}
+
+	hat.Resume()
+	body.Pause()
+	if hat.running() || !hat.Animated {
+		t.Error("the child is not paused with its parent, or loses its own status")
+	}
+	if hat.NextStep() {
+		t.Error("the child of a paused sprite goes to its next step")
+	}
+}
+
+func TestChildWorldBounds(t *testing.T) {
+	body := newTestSprite(100, 100, 20, 20)
+	body.Zoom(2)
+	hat := newTestSprite(0, 0, 10, 10)
+	body.AddChild(hat)
+	hat.VelocityX = 5
+	hat.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 110, 200), Behaviour: BoundsClamp}
+
+	hat.applyWorldBounds()
+	c := hat.corners()
+	b := boundingRect(c[:])
+	assertNear(t, "max x", b.Max.X, 110)
+	assertNear(t, "local x", hat.X, -5)
+	assertNear(t, "velocity", hat.VelocityX, 0)
+
+	// turned parent : the move up is along the local X
+	hat.Position(0, 0)
+	body.Angle = 90
+	hat.WorldBounds.Rect = NewRect(0, 0, 200, 90)
+	hat.applyWorldBounds()
+	c = hat.corners()
+	b = boundingRect(c[:])
+	assertNear(t, "turned min y", b.Min.Y, 70)
+	assertNear(t, "turned max y", b.Max.Y, 90)
+	assertNear(t, "turned local x", hat.X, 5)
+	assertNear(t, "turned local y", hat.Y, 0)
+}
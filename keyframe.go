@@ -0,0 +1,216 @@
+package sprite
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+//Easing converts a linear progression (from 0 to 1) into an eased progression
+type Easing func(t float64) float64
+
+// Predefined easings
+var (
+	// Constant speed
+	Linear Easing = func(t float64) float64 { return t }
+
+	// Start slowly and accelerate
+	EaseIn Easing = func(t float64) float64 { return t * t }
+
+	// Start quickly and decelerate
+	EaseOut Easing = func(t float64) float64 { return t * (2 - t) }
+
+	// Accelerate until the middle then decelerate
+	EaseInOut Easing = func(t float64) float64 {
+		if t < 0.5 {
+			return 2 * t * t
+		}
+		return -1 + (4-2*t)*t
+	}
+
+	// Smooth sinusoidal acceleration and deceleration
+	EaseSine Easing = func(t float64) float64 { return (1 - math.Cos(t*math.Pi)) / 2 }
+
+	// Keep the value of the keyframe until the next one
+	Hold Easing = func(t float64) float64 { return 0 }
+)
+
+//Keyframe is the value of a property at a given time of a track
+type Keyframe struct {
+	// Time of the keyframe from the start of the track (in millisecond)
+	Time int
+
+	// Value of the property at this time
+	Value float64
+
+	// Easing of the segment going from this keyframe to the next one (default is Linear)
+	Easing Easing
+}
+
+//TrackOptions contains options for a keyframe track
+type TrackOptions struct {
+	// Name of animation (default is omitted)
+	Animation string
+
+	// Name of the sprite property to animate : X, Y, Speed, Direction, ZoomX, ZoomY, Red, Green, Blue, Alpha, Angle, SkewX, SkewY,
+	// VelocityX, VelocityY, AngularVelocity, TintStrength, Saturation, Brightness, Contrast, HueRotation, Inversion
+	Property string
+
+	// Function called with the computed value, used instead of Property (Clone and the pools keep calling the same function)
+	Setter func(float64)
+
+	// Keyframes of the track, sorted by time when the track is added
+	Keyframes []Keyframe
+
+	// Restart the track at the end
+	Repeat bool
+
+	// function to launch after one complete track
+	Callback func()
+}
+
+type animationTrack struct {
+	options   *TrackOptions
+	keyframes []Keyframe
+	setter    func(float64)
+	duration  time.Duration
+	timeStart time.Time
+	finished  bool
+}
+
+// Sprite properties which can be animated by name
+var trackProperties = map[string]func(*Sprite) *float64{
+	"X":         func(s *Sprite) *float64 { return &s.X },
+	"Y":         func(s *Sprite) *float64 { return &s.Y },
+	"Speed":     func(s *Sprite) *float64 { return &s.Speed },
+	"Direction": func(s *Sprite) *float64 { return &s.Direction },
+	"ZoomX":     func(s *Sprite) *float64 { return &s.ZoomX },
+	"ZoomY":     func(s *Sprite) *float64 { return &s.ZoomY },
+	"Red":       func(s *Sprite) *float64 { return &s.Red },
+	"Green":     func(s *Sprite) *float64 { return &s.Green },
+	"Blue":      func(s *Sprite) *float64 { return &s.Blue },
+	"Alpha":     func(s *Sprite) *float64 { return &s.Alpha },
+	"Angle":     func(s *Sprite) *float64 { return &s.Angle },
+	"SkewX":     func(s *Sprite) *float64 { return &s.SkewX },
+	"SkewY":     func(s *Sprite) *float64 { return &s.SkewY },
+
+	"VelocityX":       func(s *Sprite) *float64 { return &s.VelocityX },
+	"VelocityY":       func(s *Sprite) *float64 { return &s.VelocityY },
+	"AngularVelocity": func(s *Sprite) *float64 { return &s.AngularVelocity },
+
+	"TintStrength": func(s *Sprite) *float64 { return &s.TintStrength },
+	"Saturation":   func(s *Sprite) *float64 { return &s.Saturation },
+	"Brightness":   func(s *Sprite) *float64 { return &s.Brightness },
+	"Contrast":     func(s *Sprite) *float64 { return &s.Contrast },
+	"HueRotation":  func(s *Sprite) *float64 { return &s.HueRotation },
+	"Inversion":    func(s *Sprite) *float64 { return &s.Inversion },
+}
+
+/*
+AddTrack adds a keyframe track to the sprite. Tracks are evaluated at the same time as effects
+
+The track is ignored and an error is returned when the animation or the property is unknown
+
+Example :
+
+mySprite.AddTrack(&sprite.TrackOptions{
+	Property: "SkewX",
+	Keyframes: []sprite.Keyframe{
+		{Time: 0, Value: 0, Easing: sprite.EaseInOut},
+		{Time: 500, Value: 20, Easing: sprite.EaseInOut},
+		{Time: 1000, Value: 0},
+	},
+	Repeat: true,
+})
+*/
+func (sprite *Sprite) AddTrack(options *TrackOptions) error {
+	if options.Animation == "" {
+		options.Animation = "default"
+	}
+	animation, ok := sprite.Animations[options.Animation]
+	if !ok {
+		return fmt.Errorf("sprite: unknown animation %q", options.Animation)
+	}
+
+	t := new(animationTrack)
+	t.options = options
+
+	t.setter = options.Setter
+	if t.setter == nil {
+		property, ok := trackProperties[options.Property]
+		if !ok {
+			return fmt.Errorf("sprite: unknown track property %q", options.Property)
+		}
+		value := property(sprite)
+		t.setter = func(v float64) { *value = v }
+	}
+
+	// sort a copy, the caller may reuse its keyframes
+	t.keyframes = append([]Keyframe(nil), options.Keyframes...)
+	sort.SliceStable(t.keyframes, func(i, j int) bool { return t.keyframes[i].Time < t.keyframes[j].Time })
+	if len(t.keyframes) > 0 {
+		t.duration = time.Millisecond * time.Duration(t.keyframes[len(t.keyframes)-1].Time)
+	}
+
+	animation.Tracks = append(animation.Tracks, t)
+	return nil
+}
+
+func (sprite *Sprite) applyTracks() {
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+
+	now := time.Now()
+	for _, t := range currentAnimation.Tracks {
+		if t.finished || len(t.keyframes) == 0 {
+			continue
+		}
+
+		// first drawing ? defined the time for first keyframe
+		if t.timeStart.IsZero() {
+			t.timeStart = now
+		}
+
+		elapsed := now.Sub(t.timeStart)
+		if elapsed >= t.duration { // end of the track
+			t.setter(t.keyframes[len(t.keyframes)-1].Value)
+
+			if t.options.Repeat && t.duration > 0 {
+				t.timeStart = t.timeStart.Add(elapsed / t.duration * t.duration)
+			} else {
+				t.finished = true
+			}
+
+			if t.options.Callback != nil {
+				t.options.Callback()
+			}
+			continue
+		}
+
+		t.setter(t.valueAt(elapsed))
+	}
+}
+
+// compute the value of the track at a time from the start of the track
+func (t *animationTrack) valueAt(elapsed time.Duration) float64 {
+	ms := float64(elapsed) / float64(time.Millisecond)
+
+	if ms <= float64(t.keyframes[0].Time) {
+		return t.keyframes[0].Value
+	}
+
+	for i := 1; i < len(t.keyframes); i++ {
+		to := t.keyframes[i]
+		if ms < float64(to.Time) {
+			from := t.keyframes[i-1]
+			easing := from.Easing
+			if easing == nil {
+				easing = Linear
+			}
+			where := (ms - float64(from.Time)) / float64(to.Time-from.Time)
+			return convertScale(easing(where), &scale{min: 0, max: 1}, &scale{min: from.Value, max: to.Value})
+		}
+	}
+
+	return t.keyframes[len(t.keyframes)-1].Value
+}
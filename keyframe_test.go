@@ -0,0 +1,92 @@
+package sprite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEasings(t *testing.T) {
+	for name, easing := range map[string]Easing{"Linear": Linear, "EaseIn": EaseIn, "EaseOut": EaseOut, "EaseInOut": EaseInOut, "EaseSine": EaseSine} {
+		assertNear(t, name+"(0)", easing(0), 0)
+		assertNear(t, name+"(1)", easing(1), 1)
+	}
+	assertNear(t, "EaseIn(0.5)", EaseIn(0.5), 0.25)
+	assertNear(t, "EaseOut(0.5)", EaseOut(0.5), 0.75)
+	assertNear(t, "EaseInOut(0.5)", EaseInOut(0.5), 0.5)
+	assertNear(t, "Hold(0.9)", Hold(0.9), 0)
+}
+
+func TestTrackValueAt(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	err := s.AddTrack(&TrackOptions{
+		Property: "SkewX",
+		Keyframes: []Keyframe{ // not sorted
+			{Time: 1000, Value: 0},
+			{Time: 0, Value: 0, Easing: EaseIn},
+			{Time: 500, Value: 20, Easing: Hold},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	track := s.Animations["default"].Tracks[0]
+
+	tests := []struct {
+		ms   int
+		want float64
+	}{
+		{-10, 0},
+		{0, 0},
+		{250, 5}, // EaseIn(0.5) * 20
+		{500, 20},
+		{750, 20}, // Hold
+		{1000, 0},
+		{2000, 0},
+	}
+	for _, test := range tests {
+		assertNear(t, "valueAt", track.valueAt(time.Duration(test.ms)*time.Millisecond), test.want)
+	}
+	if track.duration != time.Second {
+		t.Errorf("duration = %v, want 1s", track.duration)
+	}
+}
+
+func TestApplyTracks(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	var custom float64
+	calls := 0
+	s.AddTrack(&TrackOptions{Property: "Red", Keyframes: []Keyframe{{Time: 0, Value: 0}, {Time: 100, Value: 1}}})
+	s.AddTrack(&TrackOptions{Setter: func(v float64) { custom = v }, Keyframes: []Keyframe{{Time: 0, Value: 2}, {Time: 100, Value: 4}}, Callback: func() { calls++ }})
+
+	// halfway
+	for _, track := range s.Animations["default"].Tracks {
+		track.timeStart = time.Now().Add(-50 * time.Millisecond)
+	}
+	s.applyTracks()
+	if s.Red < 0.45 || s.Red > 0.6 || custom < 2.9 || custom > 3.2 {
+		t.Errorf("halfway Red = %v, custom = %v", s.Red, custom)
+	}
+
+	// over : last value and callback once
+	for _, track := range s.Animations["default"].Tracks {
+		track.timeStart = time.Now().Add(-time.Second)
+	}
+	s.applyTracks()
+	s.applyTracks()
+	if s.Red != 1 || custom != 4 || calls != 1 {
+		t.Errorf("end Red = %v, custom = %v, calls = %d", s.Red, custom, calls)
+	}
+}
+
+func TestAddTrackErrors(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	if err := s.AddTrack(&TrackOptions{Property: "Nope"}); err == nil {
+		t.Error("unknown property accepted")
+	}
+	if err := s.AddTrack(&TrackOptions{Animation: "walk", Property: "X"}); err == nil {
+		t.Error("unknown animation accepted")
+	}
+	if n := len(s.Animations["default"].Tracks); n != 0 {
+		t.Errorf("%d tracks added, want 0", n)
+	}
+}
@@ -0,0 +1,90 @@
+package sprite
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"math"
+	"time"
+)
+
+// Longest time integrated in one update (in second), to avoid jumps after a freeze
+const maxDeltaTime = 0.25
+
+// number of updates per second, to convert Speed (in pixel/frame) into velocity (in pixel/second)
+func framesPerSecond() float64 {
+	if tps := ebiten.MaxTPS(); tps > 0 {
+		return float64(tps)
+	}
+	return 60
+}
+
+// seconds since the last update (one frame for the first one, maxDeltaTime at most), and record this update
+func deltaTime(last *time.Time) float64 {
+	now := time.Now()
+	dt := 1 / framesPerSecond()
+	if !last.IsZero() {
+		dt = math.Min(now.Sub(*last).Seconds(), maxDeltaTime)
+	}
+	*last = now
+	return dt
+}
+
+//SetVelocity sets the velocity from a speed (in pixel/second) and a direction (in degres)
+func (sprite *Sprite) SetVelocity(speed, direction float64) {
+	angleRad := direction * math.Pi / 180 // convert degres into radians
+	sprite.VelocityX = speed * math.Cos(angleRad)
+	sprite.VelocityY = -speed * math.Sin(angleRad)
+}
+
+// integrate the kinematic body during dt seconds
+func (sprite *Sprite) integrate(dt float64) {
+	// Speed or Direction changed since the last update : they define the velocity
+	if sprite.Speed != sprite.lastSpeed || sprite.Direction != sprite.lastDirection {
+		sprite.SetVelocity(sprite.Speed*framesPerSecond(), sprite.Direction)
+	}
+
+	// acceleration and gravity
+	sprite.VelocityX += sprite.AccelerationX * dt
+	sprite.VelocityY += (sprite.AccelerationY + sprite.Gravity) * dt
+
+	// friction, drag and max speed only change the norm of the velocity
+	speed := math.Hypot(sprite.VelocityX, sprite.VelocityY)
+	if speed > 0 {
+		newSpeed := speed
+		if sprite.Friction > 0 {
+			newSpeed = math.Max(0, newSpeed-sprite.Friction*dt)
+		}
+		if sprite.Drag > 0 {
+			newSpeed *= math.Max(0, 1-sprite.Drag*dt)
+		}
+		if sprite.MaxSpeed > 0 && newSpeed > sprite.MaxSpeed {
+			newSpeed = sprite.MaxSpeed
+		}
+		if newSpeed != speed {
+			sprite.VelocityX *= newSpeed / speed
+			sprite.VelocityY *= newSpeed / speed
+		}
+	}
+
+	sprite.X += sprite.VelocityX * dt
+	sprite.Y += sprite.VelocityY * dt
+	sprite.Angle += sprite.AngularVelocity * dt
+
+	sprite.syncSpeedDirection()
+}
+
+// keep Speed and Direction in sync with the velocity
+func (sprite *Sprite) syncSpeedDirection() {
+	fps := framesPerSecond()
+	speed := math.Hypot(sprite.VelocityX, sprite.VelocityY)
+	if math.Abs(speed/fps-sprite.Speed) > 1e-9 {
+		sprite.Speed = speed / fps
+	}
+	if speed > 0 {
+		direction := math.Atan2(-sprite.VelocityY, sprite.VelocityX) * 180 / math.Pi
+		if math.Abs(math.Remainder(direction-sprite.Direction, 360)) > 1e-9 {
+			sprite.Direction = direction
+		}
+	}
+	sprite.lastSpeed = sprite.Speed
+	sprite.lastDirection = sprite.Direction
+}
@@ -0,0 +1,78 @@
+package sprite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSetVelocity(t *testing.T) {
+	tests := []struct {
+		direction  float64
+		vx, vy     float64
+		directions string
+	}{
+		{0, 10, 0, "right"},
+		{90, 0, -10, "up"},
+		{180, -10, 0, "left"},
+		{270, 0, 10, "down"},
+		{45, 10 / math.Sqrt2, -10 / math.Sqrt2, "up-right"},
+	}
+	for _, test := range tests {
+		s := newTestSprite(0, 0, 10, 10)
+		s.SetVelocity(10, test.direction)
+		assertNear(t, test.directions+" VelocityX", s.VelocityX, test.vx)
+		assertNear(t, test.directions+" VelocityY", s.VelocityY, test.vy)
+	}
+}
+
+func TestSpeedDirectionVelocity(t *testing.T) {
+	fps := framesPerSecond()
+
+	// Speed is in pixel/frame, the velocity in pixel/second
+	s := newTestSprite(0, 0, 10, 10)
+	s.Speed, s.Direction = 2, 90
+	s.integrate(0.5)
+	assertNear(t, "VelocityX", s.VelocityX, 0)
+	assertNear(t, "VelocityY", s.VelocityY, -2*fps)
+	assertNear(t, "Y", s.Y, -fps)
+
+	// the velocity defines Speed and Direction
+	s = newTestSprite(0, 0, 10, 10)
+	s.VelocityY = 3 * fps
+	s.integrate(0)
+	assertNear(t, "Speed", s.Speed, 3)
+	assertNear(t, "Direction", s.Direction, -90)
+
+	// unchanged Speed and Direction keep the velocity
+	s.VelocityX = fps
+	s.integrate(0)
+	assertNear(t, "kept VelocityX", s.VelocityX, fps)
+}
+
+func TestIntegrate(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(s *Sprite)
+		dt     float64
+		vx, vy float64
+		angle  float64
+	}{
+		{"velocity", func(s *Sprite) { s.VelocityX = 100 }, 0.5, 100, 0, 0},
+		{"acceleration", func(s *Sprite) { s.AccelerationX = 10 }, 2, 20, 0, 0},
+		{"gravity", func(s *Sprite) { s.Gravity = 10 }, 1, 0, 10, 0},
+		{"friction", func(s *Sprite) { s.VelocityX, s.Friction = 100, 50 }, 0.5, 75, 0, 0},
+		{"friction stops", func(s *Sprite) { s.VelocityX, s.Friction = 10, 50 }, 0.5, 0, 0, 0},
+		{"drag", func(s *Sprite) { s.VelocityX, s.Drag = 100, 1 }, 0.5, 50, 0, 0},
+		{"max speed", func(s *Sprite) { s.VelocityX, s.VelocityY, s.MaxSpeed = 30, 40, 10 }, 0.01, 6, 8, 0},
+		{"angular velocity", func(s *Sprite) { s.AngularVelocity = 90 }, 0.5, 0, 0, 45},
+	}
+	for _, test := range tests {
+		s := newTestSprite(0, 0, 10, 10)
+		test.setup(s)
+		s.integrate(test.dt)
+		assertNear(t, test.name+" VelocityX", s.VelocityX, test.vx)
+		assertNear(t, test.name+" VelocityY", s.VelocityY, test.vy)
+		assertNear(t, test.name+" Angle", s.Angle, test.angle)
+		assertNear(t, test.name+" X", s.X, test.vx*test.dt)
+	}
+}
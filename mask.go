@@ -0,0 +1,128 @@
+package sprite
+
+import (
+	"image"
+	"math"
+)
+
+//AlphaThreshold is the minimal alpha (from 0 to 255) of a solid pixel in the collision masks, used when animations are added (see SetAlphaThreshold for one animation)
+var AlphaThreshold uint8 = 128
+
+//Mask is the collision bitmask of one step of an animation
+type Mask struct {
+	// Size of the mask (in pixel)
+	Width, Height int
+
+	bits []uint64
+}
+
+// build the masks of all the steps of an animation
+func newMasks(source image.Image, animation *Animation, threshold uint8) []*Mask {
+	masks := make([]*Mask, animation.Steps)
+	for step := range masks {
+		masks[step] = newMask(source, animation.stepRect(step), threshold)
+	}
+	return masks
+}
+
+//newMask builds the collision mask of a rectangle of an image, a pixel is solid if its alpha reaches the threshold
+func newMask(source image.Image, r image.Rectangle, threshold uint8) *Mask {
+	r = r.Add(source.Bounds().Min)
+	m := new(Mask)
+	m.Width = r.Dx()
+	m.Height = r.Dy()
+	m.bits = make([]uint64, (m.Width*m.Height+63)/64)
+
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			_, _, _, a := source.At(r.Min.X+x, r.Min.Y+y).RGBA()
+			if a>>8 >= uint32(threshold) {
+				i := y*m.Width + x
+				m.bits[i/64] |= 1 << uint(i%64)
+			}
+		}
+	}
+	return m
+}
+
+//At reports whether the pixel is solid, pixels outside of the mask are not
+func (m *Mask) At(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.Width || y >= m.Height {
+		return false
+	}
+	i := y*m.Width + x
+	return m.bits[i/64]&(1<<uint(i%64)) != 0
+}
+
+/*
+SetAlphaThreshold builds again the collision masks of an animation with its own alpha threshold
+
+Example :
+
+mySprite.SetAlphaThreshold("explode", 32) // the smoke collides too
+*/
+func (sprite *Sprite) SetAlphaThreshold(label string, threshold uint8) {
+	animation := sprite.Animations[label]
+	if animation.source != nil {
+		animation.Masks = newMasks(animation.source, animation, threshold)
+	}
+}
+
+// mask of the current step, nil if there is none
+func (sprite *Sprite) currentMask() *Mask {
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	if currentAnimation.CurrentStep < len(currentAnimation.Masks) {
+		return currentAnimation.Masks[currentAnimation.CurrentStep]
+	}
+	return nil
+}
+
+// report whether a point of the current step is inside the hitbox, if any, and on a solid pixel of the mask
+func (sprite *Sprite) solidAt(m *Mask, x, y float64) bool {
+	if hitbox := sprite.Animations[sprite.CurrentAnimation].Hitbox; hitbox != nil && !hitbox.Contains(x, y) {
+		return false
+	}
+	return m.At(int(math.Floor(x)), int(math.Floor(y)))
+}
+
+/*
+CollidesPixel reports whether solid pixels of the current steps of the two sprites overlap on the screen
+
+Only the pixels inside the hitboxes are solid, the bounding boxes of the hitboxes are compared first
+*/
+func (sprite *Sprite) CollidesPixel(other *Sprite) bool {
+	b1, b2 := sprite.Bounds(), other.Bounds()
+	if !b1.Overlaps(b2) {
+		return false
+	}
+
+	m1, m2 := sprite.currentMask(), other.currentMask()
+	if m1 == nil || m2 == nil {
+		return false
+	}
+
+	// screen to step coordinates
+	g1, g2 := sprite.geoM(), other.geoM()
+	if !g1.IsInvertible() || !g2.IsInvertible() {
+		return false
+	}
+	g1.Invert()
+	g2.Invert()
+
+	// test the center of each pixel of the common area
+	x0, x1 := math.Floor(math.Max(b1.Min.X, b2.Min.X)), math.Ceil(math.Min(b1.Max.X, b2.Max.X))
+	y0, y1 := math.Floor(math.Max(b1.Min.Y, b2.Min.Y)), math.Ceil(math.Min(b1.Max.Y, b2.Max.Y))
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			lx, ly := g1.Apply(x+0.5, y+0.5)
+			if !sprite.solidAt(m1, lx, ly) {
+				continue
+			}
+			lx, ly = g2.Apply(x+0.5, y+0.5)
+			if other.solidAt(m2, lx, ly) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package sprite
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sprite of 10x10 pixels, opaque on the left half and translucent on the right half
+func newMaskedSprite(x, y float64) *Sprite {
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for py := 0; py < 10; py++ {
+		for px := 0; px < 10; px++ {
+			alpha := uint8(255)
+			if px >= 5 {
+				alpha = 64
+			}
+			img.Set(px, py, color.NRGBA{A: alpha})
+		}
+	}
+
+	s := newTestSprite(x, y, 10, 10)
+	a := s.Animations["default"]
+	a.source = img
+	a.Masks = newMasks(img, a, AlphaThreshold)
+	return s
+}
+
+func TestMask(t *testing.T) {
+	m := newMaskedSprite(0, 0).currentMask()
+	if !m.At(0, 0) || !m.At(4, 9) || m.At(5, 0) || m.At(-1, 0) || m.At(10, 0) {
+		t.Error("wrong solid pixels")
+	}
+}
+
+func TestCollidesPixel(t *testing.T) {
+	a := newMaskedSprite(0, 0)
+
+	// the frames overlap on the translucent half of a only
+	b := newMaskedSprite(7, 0)
+	if a.CollidesPixel(b) {
+		t.Error("translucent pixels collide")
+	}
+
+	// the frames overlap on the opaque half of a
+	b.X = 3
+	if !a.CollidesPixel(b) {
+		t.Error("opaque pixels do not collide")
+	}
+
+	// flipped, the opaque half of b is on its right (from 4 to 9)
+	b.X = 9
+	b.ZoomX = -1
+	if !a.CollidesPixel(b) {
+		t.Error("opaque pixels of the flipped sprite do not collide")
+	}
+	b.X = 13 // opaque from 8 to 13
+	if a.CollidesPixel(b) {
+		t.Error("translucent half of a collides with the flipped sprite")
+	}
+
+	// too far
+	b = newMaskedSprite(30, 0)
+	if a.CollidesPixel(b) {
+		t.Error("distant sprites collide")
+	}
+}
+
+func TestCollidesPixelHitbox(t *testing.T) {
+	a := newMaskedSprite(0, 0)
+	b := newMaskedSprite(3, 0)
+	a.SetHitbox("default", 0, 0, 2, 10) // only the two first columns
+	if a.CollidesPixel(b) {
+		t.Error("solid pixels outside of the hitbox collide")
+	}
+	b.X = 1
+	if !a.CollidesPixel(b) {
+		t.Error("solid pixels inside the hitbox do not collide")
+	}
+}
+
+func TestSetAlphaThreshold(t *testing.T) {
+	a := newMaskedSprite(0, 0)
+	b := newMaskedSprite(7, 0)
+	a.SetAlphaThreshold("default", 32)
+	if !a.CollidesPixel(b) {
+		t.Error("translucent pixels above the threshold of the animation do not collide")
+	}
+	if other := newMaskedSprite(0, 0); other.currentMask().At(7, 0) {
+		t.Error("the threshold changed the masks of another sprite")
+	}
+}
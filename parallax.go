@@ -0,0 +1,159 @@
+package sprite
+
+import (
+	"math"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Most tiles drawn by a parallax layer, when the zoom is very small
+const maxParallaxTiles = 4096
+
+//ParallaxLayer is a background sprite which scrolls slower or faster than the camera, and can be repeated infinitely
+type ParallaxLayer struct {
+	*Sprite
+
+	// Part of the movement of the camera followed by the layer (0 is fixed on the screen, 1 moves with the world)
+	ScrollX, ScrollY float64
+
+	// Repeat the frame infinitely on the X and Y axis
+	RepeatX, RepeatY bool
+
+	// Speed of the automatic scrolling in pixel/second (for clouds, water...)
+	SpeedX, SpeedY float64
+
+	// Offset of the automatic scrolling
+	offsetX, offsetY float64
+
+	// Time of the last update
+	lastUpdate time.Time
+}
+
+/*
+NewParallaxLayer creates a layer following a part of the movement of the camera
+
+Load its animations like a sprite, and draw it with a camera before the other sprites
+
+Example :
+
+clouds := sprite.NewParallaxLayer(0.2, 0)
+clouds.AddAnimation("default", "clouds.png", 0, 1, ebiten.FilterDefault)
+clouds.RepeatX = true
+clouds.SpeedX = -10
+clouds.Start()
+
+camera.Draw(surface, clouds, world)
+*/
+func NewParallaxLayer(scrollX, scrollY float64) *ParallaxLayer {
+	layer := new(ParallaxLayer)
+	layer.Sprite = NewSprite()
+	layer.ScrollX = scrollX
+	layer.ScrollY = scrollY
+	return layer
+}
+
+//Update updates the animation and the effects of the layer, and scrolls it automatically
+func (layer *ParallaxLayer) Update() {
+	dt := deltaTime(&layer.lastUpdate)
+
+	layer.Sprite.Update()
+
+	layer.offsetX += layer.SpeedX * dt
+	layer.offsetY += layer.SpeedY * dt
+
+	// keep the offset small, the repeated frames look the same
+	if w := layer.GetWidth() * layer.ZoomX; layer.RepeatX && w != 0 {
+		layer.offsetX = math.Mod(layer.offsetX, w)
+	}
+	if h := layer.GetHeight() * layer.ZoomY; layer.RepeatY && h != 0 {
+		layer.offsetY = math.Mod(layer.offsetY, h)
+	}
+}
+
+//Draw draws the layer without camera, after updating it if Update was not called since the last Draw
+func (layer *ParallaxLayer) Draw(surface *ebiten.Image) {
+	layer.prepare()
+	layer.drawView(surface, &view{})
+}
+
+func (layer *ParallaxLayer) prepare() {
+	if !layer.updated {
+		layer.Update()
+	}
+}
+
+// draw the frame as many times as needed to cover the surface
+func (layer *ParallaxLayer) drawView(surface *ebiten.Image, v *view) {
+	layer.updated = false
+	if !layer.Visible || layer.blinkHidden || v.hidden {
+		return
+	}
+
+	g := layer.viewGeoM(v)
+	if !g.IsInvertible() {
+		return
+	}
+
+	// area of the surface in the coordonnates of the frame
+	inverse := g
+	inverse.Invert()
+	var corners [4]Point
+	w, h := surface.Size()
+	for i, p := range [4]Point{{}, {X: float64(w)}, {X: float64(w), Y: float64(h)}, {Y: float64(h)}} {
+		corners[i].X, corners[i].Y = inverse.Apply(p.X, p.Y)
+	}
+	area := boundingRect(corners[:])
+
+	currentAnimation := layer.Animations[layer.CurrentAnimation]
+	r := currentAnimation.stepRect(currentAnimation.CurrentStep)
+	width, height := float64(r.Dx()), float64(r.Dy())
+	if width == 0 || height == 0 {
+		return
+	}
+
+	minX, maxX := repeatRange(layer.RepeatX, area.Min.X, area.Max.X, width)
+	minY, maxY := repeatRange(layer.RepeatY, area.Min.Y, area.Max.Y, height)
+	if (maxX-minX+1)*(maxY-minY+1) > maxParallaxTiles {
+		return
+	}
+
+	// a frame which is not repeated can be outside of the surface
+	inside := Rect{Min: Point{X: float64(minX) * width, Y: float64(minY) * height}, Max: Point{X: float64(maxX+1) * width, Y: float64(maxY+1) * height}}.Overlaps(area)
+	v.count(inside)
+	if !inside {
+		return
+	}
+
+	colorM := layer.worldColorM()
+	colorM.Concat(v.colorM)
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			options := &ebiten.DrawImageOptions{}
+			options.GeoM.Translate(float64(x)*width, float64(y)*height)
+			options.GeoM.Concat(g)
+			options.ColorM = colorM
+			options.SourceRect = &r
+			surface.DrawImage(currentAnimation.Image, options)
+		}
+	}
+}
+
+// transformation of the frame, the layer moves back with the part of the movement of the camera it does not follow
+func (layer *ParallaxLayer) viewGeoM(v *view) ebiten.GeoM {
+	g := layer.geoM()
+	g.Translate(layer.offsetX, layer.offsetY)
+	if v.camera != nil {
+		g.Translate((1-layer.ScrollX)*v.camera.X, (1-layer.ScrollY)*v.camera.Y)
+	}
+	g.Concat(v.geoM)
+	return g
+}
+
+// first and last repetitions of a frame of this size covering the area, only the frame itself when it is not repeated
+func repeatRange(repeat bool, min, max, size float64) (int, int) {
+	if !repeat {
+		return 0, 0
+	}
+	return int(math.Floor(min / size)), int(math.Floor(max / size))
+}
@@ -0,0 +1,79 @@
+package sprite
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// layer with a frame of this size and no image
+func newTestLayer(scrollX, scrollY, width, height float64) *ParallaxLayer {
+	layer := NewParallaxLayer(scrollX, scrollY)
+	layer.Animations["default"] = &Animation{Steps: 1, StepWidth: int(width), StepHeight: int(height)}
+	return layer
+}
+
+func TestParallaxScroll(t *testing.T) {
+	tests := []struct {
+		scroll       float64
+		cameraX      float64
+		wantX, wantY float64
+	}{
+		{0, 0, 100, 50},       // fixed on the screen
+		{0, 1000, 100, 50},    // whatever the camera
+		{1, 1000, -900, 50},   // moves with the world
+		{0.5, 1000, -400, 50}, // half as fast
+		{0.5, -200, 200, 50},
+	}
+	for _, test := range tests {
+		layer := newTestLayer(test.scroll, 0, 20, 10)
+		camera := NewCamera(200, 100)
+		camera.X = test.cameraX
+		g := layer.viewGeoM(&view{geoM: camera.geoM(), camera: camera})
+		x, y := g.Apply(0, 0)
+		assertNear(t, "x", x, test.wantX)
+		assertNear(t, "y", y, test.wantY)
+	}
+}
+
+func TestParallaxRepeat(t *testing.T) {
+	tests := []struct {
+		repeat              bool
+		min, max, size      float64
+		wantFirst, wantLast int
+	}{
+		{false, -500, 500, 20, 0, 0},
+		{true, 0, 100, 20, 0, 5},
+		{true, -5, 35, 20, -1, 1},
+		{true, -40, -20.5, 20, -2, -2},
+	}
+	for _, test := range tests {
+		first, last := repeatRange(test.repeat, test.min, test.max, test.size)
+		if first != test.wantFirst || last != test.wantLast {
+			t.Errorf("repeatRange(%v, %v, %v, %v) = %d, %d, want %d, %d", test.repeat, test.min, test.max, test.size, first, last, test.wantFirst, test.wantLast)
+		}
+	}
+}
+
+func TestParallaxSpeed(t *testing.T) {
+	tests := []struct {
+		repeat     bool
+		speed      float64
+		wantOffset float64
+	}{
+		{false, 50, 5}, // 50 pixels per second for 100 ms
+		{false, -250, -25},
+		{true, 250, 5}, // 25 pixels, the frame is 20 pixels wide
+		{true, -250, -5},
+	}
+	for _, test := range tests {
+		layer := newTestLayer(1, 1, 20, 10)
+		layer.RepeatX = test.repeat
+		layer.SpeedX, layer.SpeedY = test.speed, 0
+		layer.lastUpdate = time.Now().Add(-100 * time.Millisecond)
+		layer.Update()
+		if math.Abs(layer.offsetX-test.wantOffset) > math.Abs(test.speed)*0.02 || layer.offsetY != 0 {
+			t.Errorf("speed %v repeat %v : offset %v %v, want about %v 0", test.speed, test.repeat, layer.offsetX, layer.offsetY, test.wantOffset)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package sprite
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Default size of the pool of particles of an emitter
+const defaultMaxParticles = 256
+
+//Range is an interval of values, each particle picks a random value inside
+type Range struct {
+	Min, Max float64
+}
+
+// random value between Min and Max
+func (r Range) random() float64 {
+	return r.Min + rand.Float64()*(r.Max-r.Min)
+}
+
+//CurvePoint is the value of a curve at a moment of the life of a particle
+type CurvePoint struct {
+	// Moment of the life of the particle, from 0 (birth) to 1 (death)
+	Life float64
+
+	// Value of the curve at this moment
+	Value float64
+
+	// Easing of the segment going from this point to the next one (default is Linear)
+	Easing Easing
+}
+
+//Curve is a value changing over the life of a particle, its points are sorted by Life (an empty curve is always 1)
+type Curve []CurvePoint
+
+// compute the value of the curve at a moment of the life
+func (c Curve) at(life float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	if life <= c[0].Life {
+		return c[0].Value
+	}
+
+	for i := 1; i < len(c); i++ {
+		to := c[i]
+		if life < to.Life {
+			from := c[i-1]
+			easing := from.Easing
+			if easing == nil {
+				easing = Linear
+			}
+			where := (life - from.Life) / (to.Life - from.Life)
+			return convertScale(easing(where), &scale{min: 0, max: 1}, &scale{min: from.Value, max: to.Value})
+		}
+	}
+
+	return c[len(c)-1].Value
+}
+
+//Emitter spawns many lightweight particles playing the same animation, it can be drawn in a group like a sprite
+type Emitter struct {
+	// Position of the emitter in the world
+	X, Y float64
+
+	// Order of drawing inside a group
+	Z float64
+
+	// Visibility of the emitter, a hidden emitter is not updated
+	Visible bool
+
+	// Animation played by the particles
+	Animation *Animation
+
+	// Particles emitted per second between Start and Stop
+	Rate float64
+
+	// Size of the pool : the particles emitted when the pool is full are skipped
+	MaxParticles int
+
+	// Life of the particles (in second), a particle with a life of 0 dies at its birth and is never drawn
+	Lifetime Range
+
+	// Speed (in pixel/second) and direction (in degres) of the particles at their birth
+	Speed, Direction Range
+
+	// Angle at their birth and speed of rotation of the particles (in degres and degres/second)
+	Angle, Spin Range
+
+	// Zoom of the particles at their birth
+	Scale Range
+
+	// Acceleration of the particles toward the bottom (in pixel/second²)
+	Gravity float64
+
+	// Size of the rectangle centered on the emitter where the particles are born (0 is the position of the emitter)
+	SpreadX, SpreadY float64
+
+	// Multipliers of the zoom, the transparency and the colors over the life of the particles
+	ScaleCurve, AlphaCurve, RedCurve, GreenCurve, BlueCurve Curve
+
+	// Play the animation once over the life of each particle, instead of looping at its speed
+	AnimateOverLife bool
+
+	// pool of particles, the living ones first
+	particles []particle
+	alive     int
+
+	// continuous emission, and part of the next particle already emitted
+	emitting bool
+	pending  float64
+
+	// Time of the last update
+	lastUpdate time.Time
+
+	// Update was called since the last Draw
+	updated bool
+}
+
+type particle struct {
+	x, y, velocityX, velocityY float64
+	angle, spin, scale         float64
+	age, lifetime              float64
+}
+
+/*
+NewEmitter creates a visible emitter of particles playing an animation, with a pool of 256 particles
+
+"path", "duration", "steps" and "filter" are the same as AddAnimation
+
+Example :
+
+sparks := sprite.NewEmitter("gfx/explosion1.png", 500, 5, ebiten.FilterDefault)
+sparks.Lifetime = sprite.Range{Min: 0.5, Max: 1}
+sparks.Speed = sprite.Range{Min: 50, Max: 150}
+sparks.Direction = sprite.Range{Min: 0, Max: 360}
+sparks.AlphaCurve = sprite.Curve{{Life: 0.5, Value: 1}, {Life: 1, Value: 0}}
+sparks.Position(x, y)
+sparks.Burst(50)
+*/
+func NewEmitter(path string, duration int, steps int, filter ebiten.Filter) *Emitter {
+	e := new(Emitter)
+	e.Animation = newAnimation(path, duration, steps, filter)
+	e.Visible = true
+	e.MaxParticles = defaultMaxParticles
+	e.particles = make([]particle, 0, defaultMaxParticles)
+	e.Lifetime = Range{Min: 1, Max: 1}
+	e.Scale = Range{Min: 1, Max: 1}
+	return e
+}
+
+//Position moves the emitter, the living particles stay where they are
+func (e *Emitter) Position(x, y float64) {
+	e.X = x
+	e.Y = y
+}
+
+//Burst emits particles at once
+func (e *Emitter) Burst(count int) {
+	for i := 0; i < count; i++ {
+		e.emit()
+	}
+}
+
+//Start emits Rate particles per second until Stop
+func (e *Emitter) Start() {
+	e.emitting = true
+}
+
+//Stop the continuous emission, the living particles end their life
+func (e *Emitter) Stop() {
+	e.emitting = false
+	e.pending = 0
+}
+
+//Emitting returns true between Start and Stop
+func (e *Emitter) Emitting() bool {
+	return e.emitting
+}
+
+//Count returns the number of living particles
+func (e *Emitter) Count() int {
+	return e.alive
+}
+
+//Clear kills all the particles
+func (e *Emitter) Clear() {
+	e.alive = 0
+}
+
+// give birth to a particle from the pool
+func (e *Emitter) emit() {
+	if e.alive >= e.MaxParticles {
+		return
+	}
+	lifetime := e.Lifetime.random()
+	if lifetime <= 0 {
+		return
+	}
+	if e.alive == len(e.particles) {
+		e.particles = append(e.particles, particle{})
+	}
+
+	speed := e.Speed.random()
+	direction := e.Direction.random() * math.Pi / 180 // same as SetVelocity
+	e.particles[e.alive] = particle{
+		x:         e.X + (rand.Float64()-0.5)*e.SpreadX,
+		y:         e.Y + (rand.Float64()-0.5)*e.SpreadY,
+		velocityX: speed * math.Cos(direction),
+		velocityY: -speed * math.Sin(direction),
+		angle:     e.Angle.random(),
+		spin:      e.Spin.random(),
+		scale:     e.Scale.random(),
+		lifetime:  lifetime,
+	}
+	e.alive++
+}
+
+//Update emits the particles of the continuous mode, moves the living particles and kills the old ones
+func (e *Emitter) Update() {
+	e.updated = true
+
+	dt := deltaTime(&e.lastUpdate)
+
+	if !e.Visible {
+		return
+	}
+
+	for i := 0; i < e.alive; {
+		p := &e.particles[i]
+		p.age += dt
+		if p.age >= p.lifetime {
+			// the last living particle takes its place
+			e.alive--
+			e.particles[i] = e.particles[e.alive]
+			continue
+		}
+		p.velocityY += e.Gravity * dt
+		p.x += p.velocityX * dt
+		p.y += p.velocityY * dt
+		p.angle += p.spin * dt
+		i++
+	}
+
+	if e.emitting && e.Rate > 0 {
+		e.pending += e.Rate * dt
+		for ; e.pending >= 1; e.pending-- {
+			e.emit()
+		}
+	}
+}
+
+//Draw draws the particles, after updating them if Update was not called since the last Draw
+func (e *Emitter) Draw(surface *ebiten.Image) {
+	e.prepare()
+	e.drawView(surface, &view{})
+}
+
+func (e *Emitter) prepare() {
+	if !e.updated {
+		e.Update()
+	}
+}
+
+func (e *Emitter) order() (float64, float64) {
+	return e.Z, e.Y
+}
+
+// draw each particle centered on its position, transformed by the view of its group or camera
+func (e *Emitter) drawView(surface *ebiten.Image, v *view) {
+	e.updated = false
+	if !e.Visible || v.hidden || e.Animation == nil || e.Animation.Steps == 0 {
+		return
+	}
+
+	for i := 0; i < e.alive; i++ {
+		p := &e.particles[i]
+		life := p.age / p.lifetime
+
+		r := e.Animation.stepRect(e.step(p, life))
+		width, height := float64(r.Dx()), float64(r.Dy())
+
+		options := &ebiten.DrawImageOptions{}
+		zoom := p.scale * e.ScaleCurve.at(life)
+		options.GeoM.Translate(-width/2, -height/2)
+		options.GeoM.Scale(zoom, zoom)
+		options.GeoM.Rotate(deg2rad(p.angle))
+		options.GeoM.Translate(p.x, p.y)
+		options.GeoM.Concat(v.geoM)
+		if !v.visible(&options.GeoM, width, height) {
+			continue
+		}
+
+		options.ColorM.Scale(e.RedCurve.at(life), e.GreenCurve.at(life), e.BlueCurve.at(life), e.AlphaCurve.at(life))
+		options.ColorM.Concat(v.colorM)
+		options.SourceRect = &r
+		surface.DrawImage(e.Animation.Image, options)
+	}
+}
+
+// step of the animation displayed by a particle
+func (e *Emitter) step(p *particle, life float64) int {
+	steps := e.Animation.Steps
+	if e.AnimateOverLife {
+		return int(math.Min(life*float64(steps), float64(steps-1)))
+	}
+	duration := e.Animation.Duration.Seconds()
+	if duration <= 0 {
+		return 0
+	}
+	return int(p.age/duration*float64(steps)) % steps
+}
@@ -0,0 +1,159 @@
+package sprite
+
+import (
+	"testing"
+	"time"
+)
+
+// visible emitter of 10 particles living 1 second, without animation
+func newTestEmitter() *Emitter {
+	return &Emitter{Visible: true, MaxParticles: 10, Lifetime: Range{Min: 1, Max: 1}, Scale: Range{Min: 1, Max: 1}}
+}
+
+// update the emitter with the longest time step, maxDeltaTime
+func updateTestEmitter(e *Emitter) {
+	e.lastUpdate = time.Now().Add(-time.Second)
+	e.Update()
+}
+
+func TestEmitterBurst(t *testing.T) {
+	e := newTestEmitter()
+	e.Burst(4)
+	if e.Count() != 4 {
+		t.Errorf("%d particles, want 4", e.Count())
+	}
+	e.Burst(10)
+	if e.Count() != 10 {
+		t.Errorf("%d particles, want MaxParticles 10", e.Count())
+	}
+	e.Clear()
+	e.Burst(3)
+	if e.Count() != 3 || len(e.particles) != 10 {
+		t.Errorf("%d particles in a pool of %d after Clear, want 3 in 10", e.Count(), len(e.particles))
+	}
+}
+
+func TestEmitterZeroLifetime(t *testing.T) {
+	e := newTestEmitter()
+	e.Lifetime = Range{Min: 0, Max: 0}
+	e.Burst(5)
+	if e.Count() != 0 {
+		t.Errorf("%d particles with a life of 0", e.Count())
+	}
+
+	e.Lifetime = Range{Min: 1, Max: 1}
+	e.Burst(5)
+	e.Update()
+	if e.Count() != 5 {
+		t.Errorf("%d particles, want 5", e.Count())
+	}
+}
+
+func TestEmitterRate(t *testing.T) {
+	e := newTestEmitter()
+	e.Rate = 10
+	e.Start()
+
+	// 2.5 particles per update : the half is kept for the next one
+	tests := []struct {
+		count   int
+		pending float64
+	}{
+		{2, 0.5},
+		{5, 0},
+		{7, 0.5},
+	}
+	for i, test := range tests {
+		updateTestEmitter(e)
+		if e.Count() != test.count {
+			t.Errorf("update %d : %d particles, want %d", i, e.Count(), test.count)
+		}
+		assertNear(t, "pending", e.pending, test.pending)
+	}
+
+	e.Stop()
+	updateTestEmitter(e)
+	if e.Emitting() || e.pending != 0 || e.Count() != 7 {
+		t.Errorf("stopped : %d particles and %v pending, want 7 and 0", e.Count(), e.pending)
+	}
+}
+
+func TestEmitterDeath(t *testing.T) {
+	e := newTestEmitter()
+	e.Speed = Range{Min: 40, Max: 40}
+	e.Lifetime = Range{Min: 0.2, Max: 0.2}
+	e.Burst(2)
+	e.Lifetime = Range{Min: 1, Max: 1}
+	e.Burst(2)
+
+	// the dead particles are replaced by the last living ones
+	updateTestEmitter(e)
+	if e.Count() != 2 {
+		t.Fatalf("%d particles, want 2", e.Count())
+	}
+	for i := 0; i < e.Count(); i++ {
+		p := e.particles[i]
+		assertNear(t, "lifetime", p.lifetime, 1)
+		assertNear(t, "age", p.age, maxDeltaTime)
+		assertNear(t, "x", p.x, 40*maxDeltaTime)
+	}
+
+	// a hidden emitter is not updated
+	e.Visible = false
+	updateTestEmitter(e)
+	assertNear(t, "hidden age", e.particles[0].age, maxDeltaTime)
+}
+
+func TestCurve(t *testing.T) {
+	linear := Curve{{Life: 0, Value: 0}, {Life: 0.5, Value: 1}, {Life: 1, Value: 0}}
+	eased := Curve{{Life: 0.5, Value: 1, Easing: EaseIn}, {Life: 1, Value: 3}}
+	tests := []struct {
+		name  string
+		curve Curve
+		life  float64
+		want  float64
+	}{
+		{"empty", nil, 0.5, 1},
+		{"before the first point", linear, -1, 0},
+		{"linear up", linear, 0.25, 0.5},
+		{"on a point", linear, 0.5, 1},
+		{"linear down", linear, 0.75, 0.5},
+		{"after the last point", linear, 2, 0},
+		{"eased before the first point", eased, 0.2, 1},
+		{"eased", eased, 0.75, 1.5}, // EaseIn(0.5) of the way from 1 to 3
+		{"eased end", eased, 1, 3},
+	}
+	for _, test := range tests {
+		assertNear(t, test.name, test.curve.at(test.life), test.want)
+	}
+}
+
+func TestEmitterStep(t *testing.T) {
+	e := newTestEmitter()
+	e.Animation = &Animation{Steps: 4, Duration: time.Second}
+	tests := []struct {
+		overLife  bool
+		age, life float64
+		want      int
+	}{
+		{true, 0, 0, 0},
+		{true, 5, 0.5, 2},
+		{true, 9.9, 0.99, 3},
+		{true, 10, 1, 3},      // last step at the death
+		{false, 0.3, 0.03, 1}, // at the speed of the animation
+		{false, 1.3, 0.13, 1}, // looping
+	}
+	for _, test := range tests {
+		e.AnimateOverLife = test.overLife
+		p := &particle{age: test.age, lifetime: 10}
+		if got := e.step(p, test.life); got != test.want {
+			t.Errorf("AnimateOverLife %v, age %v : step %d, want %d", test.overLife, test.age, got, test.want)
+		}
+	}
+
+	e.AnimateOverLife = false
+	e.Animation.Duration = 0
+	if got := e.step(&particle{age: 0.7, lifetime: 1}, 0.7); got != 0 {
+		t.Errorf("step %d without duration, want 0", got)
+	}
+}
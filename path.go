@@ -0,0 +1,152 @@
+package sprite
+
+import (
+	"math"
+	"sort"
+)
+
+// Number of straight segments used to sample one curve
+const pathResolution = 32
+
+//Point is a position (in pixel)
+type Point struct {
+	X, Y float64
+}
+
+//Path is a curve followed at constant speed by the FollowPath effect
+type Path struct {
+	// points sampled along the curve
+	points []Point
+
+	// length of the path from the first point to each point
+	lengths []float64
+}
+
+/*
+NewPolyline creates a path made of straight lines between the points
+
+Example :
+
+path := sprite.NewPolyline(sprite.Point{X: 10, Y: 10}, sprite.Point{X: 100, Y: 10}, sprite.Point{X: 100, Y: 80})
+*/
+func NewPolyline(points ...Point) *Path {
+	return newPath(append([]Point(nil), points...))
+}
+
+//NewQuadraticBezier creates a path from a quadratic Bézier curve (p1 is the control point)
+func NewQuadraticBezier(p0, p1, p2 Point) *Path {
+	points := make([]Point, pathResolution+1)
+	for i := range points {
+		t := float64(i) / pathResolution
+		u := 1 - t
+		points[i] = Point{
+			X: u*u*p0.X + 2*u*t*p1.X + t*t*p2.X,
+			Y: u*u*p0.Y + 2*u*t*p1.Y + t*t*p2.Y,
+		}
+	}
+	return newPath(points)
+}
+
+//NewCubicBezier creates a path from a cubic Bézier curve (p1 and p2 are the control points)
+func NewCubicBezier(p0, p1, p2, p3 Point) *Path {
+	points := make([]Point, pathResolution+1)
+	for i := range points {
+		t := float64(i) / pathResolution
+		u := 1 - t
+		points[i] = Point{
+			X: u*u*u*p0.X + 3*u*u*t*p1.X + 3*u*t*t*p2.X + t*t*t*p3.X,
+			Y: u*u*u*p0.Y + 3*u*u*t*p1.Y + 3*u*t*t*p2.Y + t*t*t*p3.Y,
+		}
+	}
+	return newPath(points)
+}
+
+//NewCatmullRom creates a smooth path going through all the points
+func NewCatmullRom(points ...Point) *Path {
+	if len(points) < 3 {
+		return NewPolyline(points...)
+	}
+
+	sampled := make([]Point, 0, (len(points)-1)*pathResolution+1)
+	for i := 0; i < len(points)-1; i++ {
+		// duplicate the first and the last points to reach the ends of the curve
+		p0, p1, p2, p3 := points[0], points[i], points[i+1], points[len(points)-1]
+		if i > 0 {
+			p0 = points[i-1]
+		}
+		if i+2 < len(points) {
+			p3 = points[i+2]
+		}
+
+		for j := 0; j < pathResolution; j++ {
+			t := float64(j) / pathResolution
+			sampled = append(sampled, catmullRom(p0, p1, p2, p3, t))
+		}
+	}
+	sampled = append(sampled, points[len(points)-1])
+
+	return newPath(sampled)
+}
+
+func catmullRom(p0, p1, p2, p3 Point, t float64) Point {
+	t2 := t * t
+	t3 := t2 * t
+	return Point{
+		X: 0.5 * (2*p1.X + (-p0.X+p2.X)*t + (2*p0.X-5*p1.X+4*p2.X-p3.X)*t2 + (-p0.X+3*p1.X-3*p2.X+p3.X)*t3),
+		Y: 0.5 * (2*p1.Y + (-p0.Y+p2.Y)*t + (2*p0.Y-5*p1.Y+4*p2.Y-p3.Y)*t2 + (-p0.Y+3*p1.Y-3*p2.Y+p3.Y)*t3),
+	}
+}
+
+func newPath(points []Point) *Path {
+	path := new(Path)
+	path.points = points
+	path.lengths = make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		path.lengths[i] = path.lengths[i-1] + math.Hypot(points[i].X-points[i-1].X, points[i].Y-points[i-1].Y)
+	}
+	return path
+}
+
+//Length returns the length of the path (in pixel)
+func (path *Path) Length() float64 {
+	if len(path.lengths) == 0 {
+		return 0
+	}
+	return path.lengths[len(path.lengths)-1]
+}
+
+/*
+At returns the position at a distance from the start of the path, and the angle of the tangent (in degres)
+
+The distance is clamped between 0 and the length of the path
+*/
+func (path *Path) At(distance float64) (Point, float64) {
+	switch len(path.points) {
+	case 0:
+		return Point{}, 0
+	case 1:
+		return path.points[0], 0
+	}
+
+	// first sampled point after the distance
+	i := sort.SearchFloat64s(path.lengths, distance)
+	if i < 1 {
+		i = 1
+	} else if i > len(path.points)-1 {
+		i = len(path.points) - 1
+	}
+
+	from, to := path.points[i-1], path.points[i]
+	where := 0.0
+	if segment := path.lengths[i] - path.lengths[i-1]; segment > 0 {
+		where = math.Max(0, math.Min(1, (distance-path.lengths[i-1])/segment))
+	}
+
+	position := Point{
+		X: from.X + (to.X-from.X)*where,
+		Y: from.Y + (to.Y-from.Y)*where,
+	}
+	angle := math.Atan2(-(to.Y-from.Y), to.X-from.X) * 180 / math.Pi // the Y axis goes down
+
+	return position, angle
+}
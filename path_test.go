@@ -0,0 +1,92 @@
+package sprite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolyline(t *testing.T) {
+	path := NewPolyline(Point{X: 0, Y: 0}, Point{X: 100, Y: 0}, Point{X: 100, Y: 50})
+	assertNear(t, "Length", path.Length(), 150)
+
+	tests := []struct {
+		distance, x, y, angle float64
+	}{
+		{-10, 0, 0, 0}, // clamped
+		{0, 0, 0, 0},
+		{50, 50, 0, 0},
+		{125, 100, 25, -90}, // going down
+		{150, 100, 50, -90},
+		{500, 100, 50, -90}, // clamped
+	}
+	for _, test := range tests {
+		p, angle := path.At(test.distance)
+		assertNear(t, "X", p.X, test.x)
+		assertNear(t, "Y", p.Y, test.y)
+		assertNear(t, "angle", angle, test.angle)
+	}
+}
+
+func TestEmptyPaths(t *testing.T) {
+	if p, angle := NewPolyline().At(10); p != (Point{}) || angle != 0 {
+		t.Errorf("empty path At = %v %v", p, angle)
+	}
+	one := NewPolyline(Point{X: 3, Y: 4})
+	if p, _ := one.At(10); p != (Point{X: 3, Y: 4}) || one.Length() != 0 {
+		t.Errorf("one point path At = %v, Length = %v", p, one.Length())
+	}
+}
+
+func TestCurves(t *testing.T) {
+	p0, p1, p2, p3 := Point{X: 0, Y: 0}, Point{X: 50, Y: 100}, Point{X: 100, Y: 100}, Point{X: 150, Y: 0}
+
+	for name, path := range map[string]*Path{
+		"quadratic": NewQuadraticBezier(p0, p1, p3),
+		"cubic":     NewCubicBezier(p0, p1, p2, p3),
+	} {
+		start, _ := path.At(0)
+		end, _ := path.At(path.Length())
+		if start != p0 || !near(end.X, p3.X) || !near(end.Y, p3.Y) {
+			t.Errorf("%s goes from %v to %v", name, start, end)
+		}
+		if path.Length() <= 150 {
+			t.Errorf("%s is shorter than the straight line : %v", name, path.Length())
+		}
+	}
+
+	// Catmull-Rom goes through all the points
+	spline := NewCatmullRom(p0, p1, p2, p3)
+	for _, p := range []Point{p0, p1, p2, p3} {
+		found := false
+		for _, sampled := range spline.points {
+			if near(sampled.X, p.X) && near(sampled.Y, p.Y) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("spline does not go through %v", p)
+		}
+	}
+}
+
+func TestFollowPathRepeat(t *testing.T) {
+	s := newTestSprite(500, 500, 10, 10)
+	path := NewPolyline(Point{X: 10, Y: 20}, Point{X: 110, Y: 20})
+	s.AddEffect(&EffectOptions{Effect: FollowPath, Path: path, Duration: 100, Repeat: true, OrientToPath: true})
+
+	s.applyEffects()
+	e := s.Animations["default"].Effects[0]
+	if !e.started || s.X < 10 || s.X > 15 || !near(s.Y, 20) {
+		t.Fatalf("start at %v %v", s.X, s.Y)
+	}
+
+	// end of the first loop : the next loop starts at the first point of the path, not at the position before the path
+	e.timeEnd = time.Now().Add(-time.Millisecond)
+	s.applyEffects()
+	if !near(s.X, 10) || !near(s.Y, 20) || s.Angle != 0 {
+		t.Errorf("repeat at %v %v angle %v", s.X, s.Y, s.Angle)
+	}
+	if next := s.Animations["default"].Effects[0]; next == e || next.finished {
+		t.Error("the effect was not repeated")
+	}
+}
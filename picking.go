@@ -0,0 +1,97 @@
+package sprite
+
+import (
+	"math"
+)
+
+// position inside the current step of a point of the screen
+func (sprite *Sprite) toLocal(x, y float64) (float64, float64, bool) {
+	g := sprite.geoM()
+	if !g.IsInvertible() { // zoom at 0
+		return 0, 0, false
+	}
+	g.Invert()
+	lx, ly := g.Apply(x, y)
+	return lx, ly, true
+}
+
+/*
+ContainsPoint reports whether a point of the screen is inside the current step of the sprite (position, center, zoom, rotation and skew are inverted)
+
+Example :
+
+x, y := ebiten.CursorPosition()
+if mySprite.ContainsPoint(float64(x), float64(y)) { ... }
+*/
+func (sprite *Sprite) ContainsPoint(x, y float64) bool {
+	lx, ly, ok := sprite.toLocal(x, y)
+	return ok && lx >= 0 && ly >= 0 && lx < sprite.GetWidth() && ly < sprite.GetHeight()
+}
+
+//ContainsPixel reports whether a point of the screen is on a solid pixel of the current step (see AlphaThreshold)
+func (sprite *Sprite) ContainsPixel(x, y float64) bool {
+	lx, ly, ok := sprite.toLocal(x, y)
+	if !ok {
+		return false
+	}
+	m := sprite.currentMask()
+	return m != nil && m.At(int(math.Floor(lx)), int(math.Floor(ly)))
+}
+
+/*
+Raycast returns the first visible sprite hit by a ray, the distance from the origin and the hit point
+
+The direction does not need to be normalized, the returned sprite is nil if nothing is hit
+
+Example :
+
+hit, distance, point := sprite.Raycast(enemies, sprite.Point{X: hero.X, Y: hero.Y}, sprite.Point{X: 1, Y: 0})
+*/
+func Raycast(sprites []*Sprite, origin, direction Point) (*Sprite, float64, Point) {
+	length := math.Hypot(direction.X, direction.Y)
+	if length == 0 {
+		return nil, 0, Point{}
+	}
+	direction = Point{X: direction.X / length, Y: direction.Y / length}
+
+	var hit *Sprite
+	best := math.Inf(1)
+	for _, s := range sprites {
+		if !s.shown() {
+			continue
+		}
+		c := s.corners()
+		if d, ok := rayPolygon(origin, direction, c[:]); ok && d < best {
+			best = d
+			hit = s
+		}
+	}
+
+	if hit == nil {
+		return nil, 0, Point{}
+	}
+	return hit, best, Point{X: origin.X + direction.X*best, Y: origin.Y + direction.Y*best}
+}
+
+// distance along a normalized ray to the first edge of a polygon, 0 if the origin is inside
+func rayPolygon(origin, direction Point, polygon []Point) (float64, bool) {
+	if pointInPolygon(origin, polygon) {
+		return 0, true
+	}
+
+	best := math.Inf(1)
+	for i := range polygon {
+		a, b := polygon[i], polygon[(i+1)%len(polygon)]
+		edge := Point{X: b.X - a.X, Y: b.Y - a.Y}
+		denominator := direction.X*edge.Y - direction.Y*edge.X
+		if denominator == 0 { // parallel
+			continue
+		}
+		t := ((a.X-origin.X)*edge.Y - (a.Y-origin.Y)*edge.X) / denominator           // along the ray
+		u := ((a.X-origin.X)*direction.Y - (a.Y-origin.Y)*direction.X) / denominator // along the edge
+		if t >= 0 && u >= 0 && u <= 1 && t < best {
+			best = t
+		}
+	}
+	return best, !math.IsInf(best, 1)
+}
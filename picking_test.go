@@ -0,0 +1,79 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func TestContainsPoint(t *testing.T) {
+	s := newTestSprite(100, 100, 20, 10)
+	if !s.ContainsPoint(100, 100) || !s.ContainsPoint(119, 109) || s.ContainsPoint(120, 105) || s.ContainsPoint(99, 105) {
+		t.Error("wrong points inside the frame")
+	}
+
+	// centered and turned by 90 degres : 10 pixels wide and 20 pixels high
+	s.CenterCoordonnates = true
+	s.Angle = 90
+	if !s.ContainsPoint(100, 108) || s.ContainsPoint(108, 100) {
+		t.Error("wrong points inside the turned frame")
+	}
+
+	s.Angle = 0
+	s.Zoom(2)
+	if !s.ContainsPoint(118, 100) || s.ContainsPoint(100, 112) {
+		t.Error("wrong points inside the zoomed frame")
+	}
+
+	s.Zoom(0)
+	if s.ContainsPoint(100, 100) {
+		t.Error("a point is inside a frame zoomed at 0")
+	}
+}
+
+func TestContainsPixel(t *testing.T) {
+	s := newMaskedSprite(10, 10)
+	if !s.ContainsPixel(12, 15) || s.ContainsPixel(17, 15) || s.ContainsPixel(25, 15) {
+		t.Error("wrong solid pixels")
+	}
+
+	// flipped : the solid half is on the right
+	s.AnchorX = 0.5
+	s.X = 15
+	s.ZoomX = -1
+	if s.ContainsPixel(12, 15) || !s.ContainsPixel(17, 15) {
+		t.Error("wrong solid pixels of the flipped sprite")
+	}
+}
+
+func TestRaycast(t *testing.T) {
+	near1 := newTestSprite(50, -5, 10, 10)
+	far := newTestSprite(100, -5, 10, 10)
+	hidden := newTestSprite(20, -5, 10, 10)
+	hidden.Hide()
+	off := newTestSprite(30, 20, 10, 10)
+	sprites := []*Sprite{far, hidden, near1, off}
+
+	hit, distance, point := Raycast(sprites, Point{}, Point{X: 3, Y: 0})
+	if hit != near1 {
+		t.Fatal("the closest visible sprite is not hit")
+	}
+	assertNear(t, "distance", distance, 50)
+	assertNear(t, "point.X", point.X, 50)
+	assertNear(t, "point.Y", point.Y, 0)
+
+	// diagonal ray toward the sprite below
+	hit, distance, _ = Raycast(sprites, Point{X: 0, Y: -10}, Point{X: 1, Y: 1})
+	if hit != off {
+		t.Error("the diagonal ray misses")
+	}
+	assertNear(t, "diagonal distance", distance, 30*1.4142135623730951)
+
+	if hit, _, _ := Raycast(sprites, Point{}, Point{X: -1, Y: 0}); hit != nil {
+		t.Error("the ray hits behind its origin")
+	}
+	if hit, distance, _ := Raycast(sprites, Point{X: 55, Y: 0}, Point{X: 1, Y: 0}); hit != near1 || distance != 0 {
+		t.Error("the origin inside a sprite is not a hit at 0")
+	}
+	if hit, _, _ := Raycast(sprites, Point{}, Point{}); hit != nil {
+		t.Error("a ray without direction hits")
+	}
+}
@@ -0,0 +1,259 @@
+package sprite
+
+import (
+	"time"
+)
+
+//Pool creates sprites from a template in advance and hands them out, to avoid loading images during the game
+type Pool struct {
+	// Sprite copied by the sprites of the pool, its images are shared
+	Template *Sprite
+
+	// Create a new sprite when the pool is empty, else Acquire returns nil
+	Grow bool
+
+	// Group the acquired sprites are added to, and removed from when they are released (nil is none)
+	Group *Group
+
+	// Size, sprites in use and misses of the pool
+	Stats PoolStats
+
+	// sprites waiting to be acquired, and sprites in use
+	free  []*Sprite
+	inUse map[*Sprite]bool
+}
+
+//PoolStats counts the sprites of a pool
+type PoolStats struct {
+	// Sprites created by the pool
+	Size int
+
+	// Sprites acquired and not released yet
+	InUse int
+
+	// Acquire called while the pool was empty
+	Misses int
+}
+
+/*
+NewPool creates a pool of sprites copied from a template, which grows when it is empty
+
+A sprite returns to the pool when Release is called, when the callback of its RunOnce animation
+does not run it again, or when it is killed by its world bounds (BoundsKill).
+The sprites are copied from the template like Clone, the tracks with a Setter change the template
+
+Example :
+
+explosion := sprite.NewSprite()
+explosion.AddAnimation("default", "gfx/explosion3.png", 500, 9, ebiten.FilterDefault)
+explosions := sprite.NewPool(explosion, 20)
+explosions.Group = scene
+
+// on each explosion
+s := explosions.Acquire()
+s.Position(x, y)
+s.RunOnce(nil) // back to the pool at the end of the animation
+*/
+func NewPool(template *Sprite, size int) *Pool {
+	p := new(Pool)
+	p.Template = template
+	p.Grow = true
+	p.inUse = make(map[*Sprite]bool)
+	for i := 0; i < size; i++ {
+		p.free = append(p.free, p.newSprite())
+	}
+	return p
+}
+
+// create a hidden sprite belonging to the pool
+func (p *Pool) newSprite() *Sprite {
+	s := p.Template.Clone()
+	s.Hide()
+	s.pool = p
+	p.Stats.Size++
+	return s
+}
+
+/*
+Acquire returns a sprite of the pool reset as the template, and adds it to the group of the pool
+
+It returns nil when the pool is empty and cannot grow
+*/
+func (p *Pool) Acquire() *Sprite {
+	var s *Sprite
+	if n := len(p.free); n > 0 {
+		s = p.free[n-1]
+		p.free[n-1] = nil
+		p.free = p.free[:n-1]
+		s.copyFrom(p.Template)
+	} else {
+		p.Stats.Misses++
+		if !p.Grow {
+			return nil
+		}
+		s = p.newSprite()
+		s.copyFrom(p.Template)
+	}
+
+	p.inUse[s] = true
+	p.Stats.InUse++
+	if p.Group != nil {
+		p.Group.Add(s)
+	}
+	return s
+}
+
+//Release hides a sprite and gives it back to the pool, it does nothing if the sprite is not in use
+func (p *Pool) Release(s *Sprite) {
+	if !p.inUse[s] {
+		return
+	}
+	delete(p.inUse, s)
+	p.Stats.InUse--
+	s.Hide()
+	if p.Group != nil {
+		p.Group.Remove(s)
+	}
+	p.free = append(p.free, s)
+}
+
+//InUse returns true if the sprite was acquired from the pool and not released yet
+func (p *Pool) InUse(s *Sprite) bool {
+	return p.inUse[s]
+}
+
+// give the sprite back to its pool, if any
+func (sprite *Sprite) releaseToPool() {
+	if sprite.pool != nil {
+		sprite.pool.Release(sprite)
+	}
+}
+
+/*
+Clone creates a copy of the sprite sharing its images and collision masks
+
+The position, the movement, the colours, the hitboxes, the pivots, the collision shapes and the keyframe tracks are copied,
+the running effects, the children, the parent and the queued animations are not.
+A track with a Setter still calls the same function, which usually changes the original sprite : use Property, or add the track again to the copy
+
+Example :
+
+bat2 := bat.Clone()
+bat2.Position(100, 50)
+*/
+func (sprite *Sprite) Clone() *Sprite {
+	clone := NewSprite()
+	clone.copyFrom(sprite)
+	return clone
+}
+
+// copy the hitbox, the pivots and the shapes of the template, they can be changed without changing the template
+func (animation *Animation) copyCollisions(template *Animation) {
+	if template.Hitbox != nil {
+		hitbox := *template.Hitbox
+		animation.Hitbox = &hitbox
+	}
+	if template.Pivot != nil {
+		pivot := *template.Pivot
+		animation.Pivot = &pivot
+	}
+	if template.StepPivots != nil {
+		animation.StepPivots = make(map[int]Point, len(template.StepPivots))
+		for step, p := range template.StepPivots {
+			animation.StepPivots[step] = p
+		}
+	}
+	if template.Shapes != nil {
+		animation.Shapes = make([]*FrameShape, len(template.Shapes))
+		for i, s := range template.Shapes {
+			shape := *s
+			shape.Steps = append([]int(nil), s.Steps...)
+			animation.Shapes[i] = &shape
+		}
+	}
+}
+
+// reset the properties and the animations of the sprite as the template
+func (sprite *Sprite) copyFrom(template *Sprite) {
+	sprite.CurrentAnimation = template.CurrentAnimation
+	sprite.X, sprite.Y = template.X, template.Y
+	sprite.Speed, sprite.Direction = template.Speed, template.Direction
+	sprite.VelocityX, sprite.VelocityY = template.VelocityX, template.VelocityY
+	sprite.AccelerationX, sprite.AccelerationY = template.AccelerationX, template.AccelerationY
+	sprite.Gravity = template.Gravity
+	sprite.Drag = template.Drag
+	sprite.Friction = template.Friction
+	sprite.MaxSpeed = template.MaxSpeed
+	sprite.AngularVelocity = template.AngularVelocity
+	sprite.ZoomX, sprite.ZoomY = template.ZoomX, template.ZoomY
+	sprite.Red, sprite.Green, sprite.Blue = template.Red, template.Green, template.Blue
+	sprite.Alpha = template.Alpha
+	sprite.Tint = template.Tint
+	sprite.TintStrength = template.TintStrength
+	sprite.Saturation = template.Saturation
+	sprite.Brightness = template.Brightness
+	sprite.Contrast = template.Contrast
+	sprite.HueRotation = template.HueRotation
+	sprite.Inversion = template.Inversion
+	sprite.Angle = template.Angle
+	sprite.SkewX, sprite.SkewY = template.SkewX, template.SkewY
+	sprite.Z = template.Z
+	sprite.Visible = template.Visible
+	sprite.Animated = template.Animated
+	sprite.CenterCoordonnates = template.CenterCoordonnates
+	sprite.AnchorX, sprite.AnchorY = template.AnchorX, template.AnchorY
+	sprite.Borders = template.Borders
+	sprite.WorldBounds = nil
+	if template.WorldBounds != nil {
+		bounds := *template.WorldBounds
+		sprite.WorldBounds = &bounds
+	}
+	sprite.Collision = template.Collision
+	sprite.CollisionLayer = template.CollisionLayer
+	sprite.CollisionMask = template.CollisionMask
+
+	sprite.shakeX, sprite.shakeY = 0, 0
+	sprite.blinkHidden = false
+	sprite.lastSpeed, sprite.lastDirection = template.lastSpeed, template.lastDirection
+	sprite.lastUpdate = time.Time{}
+	sprite.updated = false
+
+	// the effects and the animations left are over
+	sprite.dropQueue()
+	for _, a := range sprite.Animations {
+		for _, e := range a.Effects {
+			if e != nil && e.options.handle != nil {
+				e.options.handle.close()
+			}
+		}
+		if a.runOnceHandle != nil {
+			a.runOnceHandle.close()
+		}
+	}
+
+	now := time.Now()
+	for label, t := range template.Animations {
+		a, ok := sprite.Animations[label]
+		if !ok {
+			a = new(Animation)
+			sprite.Animations[label] = a
+		}
+		effects := a.Effects[:0]
+		*a = *t // the image and the masks are shared
+		a.copyCollisions(t)
+		a.CurrentStep = a.FirstStep
+		a.Effects = effects
+		a.Tracks = nil
+		a.RunOnce = false
+		a.callbackAfterRunOnce = nil
+		a.runOnceHandle = nil
+		a.currentStepTimeStart = now
+	}
+
+	// the tracks set the properties of this sprite
+	for _, t := range template.Animations {
+		for _, track := range t.Tracks {
+			sprite.AddTrack(track.options)
+		}
+	}
+}
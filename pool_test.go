@@ -0,0 +1,91 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func newTestPool(size int) (*Pool, *Sprite) {
+	template := newTestSprite(5, 5, 10, 10)
+	a := template.Animations["default"]
+	a.Hitbox = &Rect{Max: Point{X: 8, Y: 8}}
+	a.Pivot = &Point{X: 5, Y: 5}
+	a.StepPivots = map[int]Point{0: {X: 1, Y: 1}}
+	template.AddShape("default", ShapeHurtbox, Circle{X: 5, Y: 5, Radius: 4}, 0)
+	return NewPool(template, size), template
+}
+
+func TestPoolAcquireRelease(t *testing.T) {
+	p, _ := newTestPool(2)
+	p.Group = NewGroup()
+
+	a := p.Acquire()
+	b := p.Acquire()
+	if a == nil || b == nil || a == b {
+		t.Fatal("wrong sprites acquired")
+	}
+	if !a.Visible || !p.InUse(a) || !p.Group.Contains(a) {
+		t.Error("the acquired sprite is not ready")
+	}
+	if p.Stats.Size != 2 || p.Stats.InUse != 2 || p.Stats.Misses != 0 {
+		t.Errorf("stats %+v", p.Stats)
+	}
+
+	// empty pool
+	c := p.Acquire()
+	if c == nil || p.Stats.Size != 3 || p.Stats.Misses != 1 {
+		t.Errorf("the pool does not grow, stats %+v", p.Stats)
+	}
+	p.Grow = false
+	if p.Acquire() != nil {
+		t.Error("the pool grows")
+	}
+
+	a.Position(100, 100)
+	p.Release(a)
+	p.Release(a) // nothing
+	if a.Visible || p.InUse(a) || p.Group.Contains(a) || p.Stats.InUse != 2 {
+		t.Errorf("the sprite is not released, stats %+v", p.Stats)
+	}
+
+	// reused and reset as the template
+	if again := p.Acquire(); again != a || a.X != 5 || a.Y != 5 {
+		t.Error("the released sprite is not reused and reset")
+	}
+}
+
+func TestPoolCopies(t *testing.T) {
+	p, template := newTestPool(1)
+	s := p.Acquire()
+	a := s.Animations["default"]
+	a.Hitbox.Max.X = 2
+	a.Pivot.X = 0
+	a.StepPivots[0] = Point{}
+	a.Shapes[0].Steps[0] = 3
+	s.AddShape("default", ShapeHitbox, Circle{Radius: 1})
+
+	original := template.Animations["default"]
+	if original.Hitbox.Max.X != 8 || original.Pivot.X != 5 || original.StepPivots[0].X != 1 {
+		t.Error("the hitbox or the pivots of the template are changed")
+	}
+	if len(original.Shapes) != 1 || original.Shapes[0].Steps[0] != 0 {
+		t.Error("the shapes of the template are changed")
+	}
+
+	p.Release(s)
+	s = p.Acquire()
+	a = s.Animations["default"]
+	if a.Hitbox.Max.X != 8 || len(a.Shapes) != 1 || a.StepPivots[0].X != 1 {
+		t.Error("the sprite is not reset as the template")
+	}
+}
+
+func TestPoolKill(t *testing.T) {
+	p, template := newTestPool(1)
+	template.WorldBounds = &WorldBounds{Rect: NewRect(0, 0, 50, 50), Behaviour: BoundsKill}
+	s := p.Acquire()
+	s.Position(200, 200)
+	s.applyWorldBounds()
+	if p.InUse(s) || p.Stats.InUse != 0 {
+		t.Error("the killed sprite is not back in the pool")
+	}
+}
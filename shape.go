@@ -0,0 +1,316 @@
+package sprite
+
+import (
+	"github.com/hajimehoshi/ebiten"
+	"image/color"
+	"math"
+)
+
+// Constant kinds of collision shapes
+const (
+	// Shape which hurts the others (a sword, a bullet)
+	ShapeHitbox = iota
+
+	// Shape which can be hurt (the body)
+	ShapeHurtbox
+)
+
+// Number of lines used to draw a circle
+const circleResolution = 24
+
+var red = color.RGBA{R: 255, G: 0, B: 0, A: 255}
+
+//Shape is a collision shape in the coordinates of a step (in pixel from the top-left corner of the step)
+type Shape interface {
+	// shape on the screen once transformed
+	transform(g *ebiten.GeoM) transformedShape
+}
+
+//Circle is a circular collision shape
+type Circle struct {
+	X, Y, Radius float64
+}
+
+//Polygon is a convex collision shape
+type Polygon struct {
+	Points []Point
+}
+
+//Capsule is a segment with a radius, like a stretched circle
+type Capsule struct {
+	A, B   Point
+	Radius float64
+}
+
+//FrameShape is a collision shape of an animation, active on some steps only
+type FrameShape struct {
+	// Kind= ShapeHitbox or ShapeHurtbox
+	Kind int
+
+	// Shape= Circle, Polygon or Capsule
+	Shape Shape
+
+	// Steps where the shape is active (empty is all the steps)
+	Steps []int
+}
+
+// shape on the screen : a convex polygon, or a segment with a radius (a circle if A==B)
+type transformedShape struct {
+	polygon []Point
+	a, b    Point
+	radius  float64
+	round   bool
+}
+
+func (c Circle) transform(g *ebiten.GeoM) transformedShape {
+	x, y := g.Apply(c.X, c.Y)
+	return transformedShape{a: Point{X: x, Y: y}, b: Point{X: x, Y: y}, radius: c.Radius * radiusScale(g), round: true}
+}
+
+func (p Polygon) transform(g *ebiten.GeoM) transformedShape {
+	points := make([]Point, len(p.Points))
+	for i, point := range p.Points {
+		points[i].X, points[i].Y = g.Apply(point.X, point.Y)
+	}
+	return transformedShape{polygon: points}
+}
+
+func (c Capsule) transform(g *ebiten.GeoM) transformedShape {
+	ax, ay := g.Apply(c.A.X, c.A.Y)
+	bx, by := g.Apply(c.B.X, c.B.Y)
+	return transformedShape{a: Point{X: ax, Y: ay}, b: Point{X: bx, Y: by}, radius: c.Radius * radiusScale(g), round: true}
+}
+
+// mean scale of a transformation, radiuses stay round with a non uniform zoom or a skew
+func radiusScale(g *ebiten.GeoM) float64 {
+	det := g.Element(0, 0)*g.Element(1, 1) - g.Element(0, 1)*g.Element(1, 0)
+	return math.Sqrt(math.Abs(det))
+}
+
+/*
+AddShape adds a collision shape to an animation, active on the given steps (all the steps if omitted)
+
+Example :
+
+mySprite.AddShape("attack-right", sprite.ShapeHitbox, sprite.Capsule{A: sprite.Point{X: 20, Y: 10}, B: sprite.Point{X: 40, Y: 10}, Radius: 4}, 3, 4)
+*/
+func (sprite *Sprite) AddShape(label string, kind int, shape Shape, steps ...int) {
+	animation := sprite.Animations[label]
+	animation.Shapes = append(animation.Shapes, &FrameShape{Kind: kind, Shape: shape, Steps: steps})
+}
+
+// active shapes of a kind for the current step, once transformed on the screen
+func (sprite *Sprite) activeShapes(kind int) []transformedShape {
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	var shapes []transformedShape
+	var g ebiten.GeoM
+	computed := false
+
+	for _, s := range currentAnimation.Shapes {
+		if s.Kind != kind || !s.activeOn(currentAnimation.CurrentStep) {
+			continue
+		}
+		if !computed {
+			g = sprite.geoM()
+			computed = true
+		}
+		shapes = append(shapes, s.Shape.transform(&g))
+	}
+	return shapes
+}
+
+// bounds of the frame and of all the shapes of the current animation, the shapes can go past the frame (a sword)
+func (sprite *Sprite) broadBounds() Rect {
+	r := sprite.Bounds()
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	if currentAnimation == nil || len(currentAnimation.Shapes) == 0 {
+		return r
+	}
+
+	g := sprite.geoM()
+	for _, s := range currentAnimation.Shapes {
+		shape := s.Shape.transform(&g)
+		b := shape.bounds()
+		r.Min.X = math.Min(r.Min.X, b.Min.X)
+		r.Min.Y = math.Min(r.Min.Y, b.Min.Y)
+		r.Max.X = math.Max(r.Max.X, b.Max.X)
+		r.Max.Y = math.Max(r.Max.Y, b.Max.Y)
+	}
+	return r
+}
+
+func (s *FrameShape) activeOn(step int) bool {
+	if len(s.Steps) == 0 {
+		return true
+	}
+	for _, st := range s.Steps {
+		if st == step {
+			return true
+		}
+	}
+	return false
+}
+
+//Hits reports whether an active hitbox of the sprite overlaps an active hurtbox of the other sprite
+func (sprite *Sprite) Hits(other *Sprite) bool {
+	return shapesOverlap(sprite.activeShapes(ShapeHitbox), other.activeShapes(ShapeHurtbox))
+}
+
+/*
+OverlapsShape reports whether an active hurtbox of the sprite, or its hitbox if it has no hurtbox, overlaps a shape of the world
+
+Example :
+
+for _, object := range level.ObjectLayer("walls").Objects {
+	if object.Shape != nil && hero.OverlapsShape(object.Shape) {
+		// blocked
+	}
+}
+*/
+func (sprite *Sprite) OverlapsShape(shape Shape) bool {
+	shapes := sprite.activeShapes(ShapeHurtbox)
+	if len(shapes) == 0 {
+		c := sprite.hitboxCorners()
+		shapes = []transformedShape{{polygon: c[:]}}
+	}
+	return shapesOverlap(shapes, []transformedShape{shape.transform(&ebiten.GeoM{})})
+}
+
+//HurtboxesOverlap reports whether active hurtboxes of the two sprites overlap
+func (sprite *Sprite) HurtboxesOverlap(other *Sprite) bool {
+	return shapesOverlap(sprite.activeShapes(ShapeHurtbox), other.activeShapes(ShapeHurtbox))
+}
+
+func shapesOverlap(a, b []transformedShape) bool {
+	for i := range a {
+		for j := range b {
+			if a[i].overlaps(&b[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *transformedShape) overlaps(other *transformedShape) bool {
+	switch {
+	case s.round && other.round:
+		return segmentsDistance(s.a, s.b, other.a, other.b) <= s.radius+other.radius
+	case s.round:
+		return segmentPolygonDistance(s.a, s.b, other.polygon) <= s.radius
+	case other.round:
+		return segmentPolygonDistance(other.a, other.b, s.polygon) <= other.radius
+	default:
+		return polygonsOverlap(s.polygon, other.polygon)
+	}
+}
+
+// smallest rectangle containing the shape
+func (s *transformedShape) bounds() Rect {
+	if !s.round {
+		return boundingRect(s.polygon)
+	}
+	r := boundingRect([]Point{s.a, s.b})
+	r.Min.X -= s.radius
+	r.Min.Y -= s.radius
+	r.Max.X += s.radius
+	r.Max.Y += s.radius
+	return r
+}
+
+// distance between a point and a segment
+func pointSegmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/length))
+	}
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+// side of the point c from the line ab
+func cross(a, b, c Point) float64 {
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+func segmentsIntersect(a, b, c, d Point) bool {
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	return ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0))
+}
+
+// distance between two segments
+func segmentsDistance(a, b, c, d Point) float64 {
+	if segmentsIntersect(a, b, c, d) {
+		return 0
+	}
+	return math.Min(
+		math.Min(pointSegmentDistance(a, c, d), pointSegmentDistance(b, c, d)),
+		math.Min(pointSegmentDistance(c, a, b), pointSegmentDistance(d, a, b)),
+	)
+}
+
+// point inside a convex or concave polygon (ray casting)
+func pointInPolygon(p Point, polygon []Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		pi, pj := polygon[i], polygon[j]
+		if (pi.Y > p.Y) != (pj.Y > p.Y) && p.X < (pj.X-pi.X)*(p.Y-pi.Y)/(pj.Y-pi.Y)+pi.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distance between a segment and a polygon (0 if they overlap)
+func segmentPolygonDistance(a, b Point, polygon []Point) float64 {
+	if len(polygon) == 0 {
+		return math.Inf(1)
+	}
+	if pointInPolygon(a, polygon) {
+		return 0
+	}
+	distance := math.Inf(1)
+	for i := range polygon {
+		distance = math.Min(distance, segmentsDistance(a, b, polygon[i], polygon[(i+1)%len(polygon)]))
+	}
+	return distance
+}
+
+// draw the active shapes of the current step, hitboxes in red and hurtboxes with the color
+func (sprite *Sprite) drawShapes(surface *ebiten.Image, c color.Color, g *ebiten.GeoM) {
+	for _, kind := range [2]int{ShapeHurtbox, ShapeHitbox} {
+		clr := c
+		if kind == ShapeHitbox {
+			clr = red
+		}
+		for _, s := range sprite.activeShapes(kind) {
+			s.draw(surface, clr, g)
+		}
+	}
+}
+
+func (s *transformedShape) draw(surface *ebiten.Image, c color.Color, g *ebiten.GeoM) {
+	if !s.round {
+		for i := range s.polygon {
+			drawLine(surface, s.polygon[i], s.polygon[(i+1)%len(s.polygon)], c, g)
+		}
+		return
+	}
+
+	// a circle at each end of the segment, joined by two lines
+	for _, center := range [2]Point{s.a, s.b} {
+		for i := 0; i < circleResolution; i++ {
+			a1 := 2 * math.Pi * float64(i) / circleResolution
+			a2 := 2 * math.Pi * float64(i+1) / circleResolution
+			drawLine(surface,
+				Point{X: center.X + s.radius*math.Cos(a1), Y: center.Y + s.radius*math.Sin(a1)},
+				Point{X: center.X + s.radius*math.Cos(a2), Y: center.Y + s.radius*math.Sin(a2)}, c, g)
+		}
+	}
+	if length := math.Hypot(s.b.X-s.a.X, s.b.Y-s.a.Y); length > 0 {
+		nx, ny := -(s.b.Y-s.a.Y)/length*s.radius, (s.b.X-s.a.X)/length*s.radius
+		drawLine(surface, Point{X: s.a.X + nx, Y: s.a.Y + ny}, Point{X: s.b.X + nx, Y: s.b.Y + ny}, c, g)
+		drawLine(surface, Point{X: s.a.X - nx, Y: s.a.Y - ny}, Point{X: s.b.X - nx, Y: s.b.Y - ny}, c, g)
+	}
+}
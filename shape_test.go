@@ -0,0 +1,95 @@
+package sprite
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+func TestShapesOverlap(t *testing.T) {
+	var identity ebiten.GeoM
+	square := Polygon{Points: []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}}
+	tests := []struct {
+		name string
+		a, b Shape
+		want bool
+	}{
+		{"circles", Circle{X: 0, Y: 0, Radius: 5}, Circle{X: 8, Y: 0, Radius: 4}, true},
+		{"distant circles", Circle{X: 0, Y: 0, Radius: 5}, Circle{X: 10, Y: 0, Radius: 4}, false},
+		{"circle inside polygon", Circle{X: 5, Y: 5, Radius: 1}, square, true},
+		{"circle near polygon", Circle{X: 13, Y: 5, Radius: 2}, square, false},
+		{"circle touching polygon corner", Circle{X: 12, Y: 12, Radius: 3}, square, true},
+		{"capsule crossing polygon", Capsule{A: Point{X: -5, Y: 5}, B: Point{X: 15, Y: 5}, Radius: 1}, square, true},
+		{"capsule along polygon", Capsule{A: Point{X: -5, Y: 12}, B: Point{X: 15, Y: 12}, Radius: 1.5}, square, false},
+		{"crossing capsules", Capsule{A: Point{X: 0, Y: 0}, B: Point{X: 10, Y: 10}}, Capsule{A: Point{X: 0, Y: 10}, B: Point{X: 10, Y: 0}}, true},
+		{"parallel capsules", Capsule{A: Point{X: 0, Y: 0}, B: Point{X: 10, Y: 0}, Radius: 2}, Capsule{A: Point{X: 0, Y: 5}, B: Point{X: 10, Y: 5}, Radius: 2}, false},
+		{"polygons", square, Polygon{Points: []Point{{X: 5, Y: 5}, {X: 20, Y: 5}, {X: 20, Y: 20}}}, true},
+	}
+	for _, test := range tests {
+		a, b := test.a.transform(&identity), test.b.transform(&identity)
+		if got := a.overlaps(&b); got != test.want {
+			t.Errorf("%s : %v, want %v", test.name, got, test.want)
+		}
+		if got := b.overlaps(&a); got != test.want {
+			t.Errorf("%s reversed : %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestTransformedRadius(t *testing.T) {
+	var g ebiten.GeoM
+	g.Scale(2, 2)
+	g.Rotate(1)
+	g.Translate(100, 50)
+	c := Circle{X: 1, Y: 0, Radius: 3}.transform(&g)
+	assertNear(t, "radius", c.radius, 6)
+	if !c.round || c.a != c.b {
+		t.Error("the circle is not a round point")
+	}
+}
+
+func TestHitsActiveSteps(t *testing.T) {
+	attacker := newTestSprite(0, 0, 20, 20)
+	attacker.Animations["default"].Steps = 4
+	attacker.AddShape("default", ShapeHitbox, Capsule{A: Point{X: 20, Y: 10}, B: Point{X: 40, Y: 10}, Radius: 2}, 2, 3)
+
+	target := newTestSprite(35, 0, 20, 20)
+	target.AddShape("default", ShapeHurtbox, Circle{X: 5, Y: 10, Radius: 3})
+
+	if attacker.Hits(target) {
+		t.Error("the hitbox hits on an inactive step")
+	}
+	attacker.Animations["default"].CurrentStep = 2
+	if !attacker.Hits(target) {
+		t.Error("the hitbox does not hit on an active step")
+	}
+	if target.Hits(attacker) {
+		t.Error("a sprite without hitbox hits")
+	}
+
+	// flipped : the sword is on the other side
+	attacker.AnchorX = 0.5
+	attacker.X = 10
+	attacker.ZoomX = -1
+	if attacker.Hits(target) {
+		t.Error("the flipped hitbox hits")
+	}
+}
+
+func TestOverlapsShape(t *testing.T) {
+	s := newTestSprite(0, 0, 10, 10)
+	wall := Polygon{Points: []Point{{X: 12, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 12, Y: 10}}}
+	if s.OverlapsShape(wall) {
+		t.Error("the frame overlaps the wall")
+	}
+	s.X = 5
+	if !s.OverlapsShape(wall) {
+		t.Error("the frame does not overlap the wall")
+	}
+
+	// the hurtbox is used instead of the frame
+	s.AddShape("default", ShapeHurtbox, Circle{X: 2, Y: 5, Radius: 2})
+	if s.OverlapsShape(wall) {
+		t.Error("the hurtbox overlaps the wall")
+	}
+}
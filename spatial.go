@@ -0,0 +1,537 @@
+package sprite
+
+import (
+	"math"
+)
+
+//SpatialIndex indexes sprites by their bounds to find the close ones quickly, the bounds include the collision shapes going past the frame
+type SpatialIndex interface {
+	// Insert adds a sprite to the index
+	Insert(s *Sprite)
+
+	// Remove removes a sprite from the index
+	Remove(s *Sprite)
+
+	// Update moves a sprite inside the index after a change of its bounds
+	Update(s *Sprite)
+
+	// UpdateAll updates all the sprites of the index
+	UpdateAll()
+
+	// Query returns the sprites whose bounds overlap the rectangle
+	Query(r Rect) []*Sprite
+
+	// Nearest returns the sprite whose bounds are the closest to the point, nil if the index is empty
+	Nearest(x, y float64) *Sprite
+
+	// Pairs calls the function for each pair of sprites whose bounds overlap
+	Pairs(f func(a, b *Sprite))
+}
+
+var (
+	_ SpatialIndex = (*SpatialHash)(nil)
+	_ SpatialIndex = (*Quadtree)(nil)
+)
+
+// distance between a point and a rectangle (0 inside)
+func rectDistance(r Rect, x, y float64) float64 {
+	dx := math.Max(0, math.Max(r.Min.X-x, x-r.Max.X))
+	dy := math.Max(0, math.Max(r.Min.Y-y, y-r.Max.Y))
+	return math.Hypot(dx, dy)
+}
+
+//////////////////////////////////////////// SPATIAL HASH ////////////////////////////////////////////
+
+type cell struct {
+	x, y int
+}
+
+type hashEntry struct {
+	bounds   Rect
+	min, max cell
+}
+
+//SpatialHash is a grid of cells of the same size, good for many sprites of similar sizes
+type SpatialHash struct {
+	// Size of a cell (in pixel)
+	CellSize float64
+
+	cells   map[cell][]*Sprite
+	entries map[*Sprite]*hashEntry
+
+	// cells covering all the sprites, computed again after a sprite left one of its borders
+	extentMin, extentMax cell
+	extentDirty          bool
+}
+
+/*
+NewSpatialHash creates a spatial hash grid, the cell size should be about twice the size of the sprites
+
+Example :
+
+grid := sprite.NewSpatialHash(64)
+*/
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	h := new(SpatialHash)
+	h.CellSize = cellSize
+	h.cells = make(map[cell][]*Sprite)
+	h.entries = make(map[*Sprite]*hashEntry)
+	return h
+}
+
+func (h *SpatialHash) cellAt(x, y float64) cell {
+	return cell{x: int(math.Floor(x / h.CellSize)), y: int(math.Floor(y / h.CellSize))}
+}
+
+func (h *SpatialHash) addToCells(s *Sprite, e *hashEntry) {
+	if len(h.entries) == 1 {
+		h.extentMin, h.extentMax = e.min, e.max
+		h.extentDirty = false
+	} else {
+		h.extentMin = cell{x: minInt(h.extentMin.x, e.min.x), y: minInt(h.extentMin.y, e.min.y)}
+		h.extentMax = cell{x: maxInt(h.extentMax.x, e.max.x), y: maxInt(h.extentMax.y, e.max.y)}
+	}
+	for y := e.min.y; y <= e.max.y; y++ {
+		for x := e.min.x; x <= e.max.x; x++ {
+			c := cell{x: x, y: y}
+			h.cells[c] = append(h.cells[c], s)
+		}
+	}
+}
+
+func (h *SpatialHash) removeFromCells(s *Sprite, e *hashEntry) {
+	if e.min.x == h.extentMin.x || e.min.y == h.extentMin.y || e.max.x == h.extentMax.x || e.max.y == h.extentMax.y {
+		h.extentDirty = true
+	}
+	for y := e.min.y; y <= e.max.y; y++ {
+		for x := e.min.x; x <= e.max.x; x++ {
+			c := cell{x: x, y: y}
+			sprites := h.cells[c]
+			for i, other := range sprites {
+				if other == s {
+					sprites[i] = sprites[len(sprites)-1]
+					sprites = sprites[:len(sprites)-1]
+					break
+				}
+			}
+			if len(sprites) == 0 {
+				delete(h.cells, c)
+			} else {
+				h.cells[c] = sprites
+			}
+		}
+	}
+}
+
+//Insert adds a sprite to the grid
+func (h *SpatialHash) Insert(s *Sprite) {
+	if _, ok := h.entries[s]; ok {
+		h.Update(s)
+		return
+	}
+	b := s.broadBounds()
+	e := &hashEntry{bounds: b, min: h.cellAt(b.Min.X, b.Min.Y), max: h.cellAt(b.Max.X, b.Max.Y)}
+	h.entries[s] = e
+	h.addToCells(s, e)
+}
+
+//Remove removes a sprite from the grid
+func (h *SpatialHash) Remove(s *Sprite) {
+	if e, ok := h.entries[s]; ok {
+		h.removeFromCells(s, e)
+		delete(h.entries, s)
+	}
+}
+
+//Update moves a sprite to its new cells, only if they changed
+func (h *SpatialHash) Update(s *Sprite) {
+	e, ok := h.entries[s]
+	if !ok {
+		h.Insert(s)
+		return
+	}
+	b := s.broadBounds()
+	min, max := h.cellAt(b.Min.X, b.Min.Y), h.cellAt(b.Max.X, b.Max.Y)
+	if min != e.min || max != e.max {
+		h.removeFromCells(s, e)
+		e.min, e.max = min, max
+		h.addToCells(s, e)
+	}
+	e.bounds = b
+}
+
+//UpdateAll updates all the sprites of the grid
+func (h *SpatialHash) UpdateAll() {
+	for s := range h.entries {
+		h.Update(s)
+	}
+}
+
+//Query returns the sprites whose bounds overlap the rectangle
+func (h *SpatialHash) Query(r Rect) []*Sprite {
+	var result []*Sprite
+	seen := make(map[*Sprite]bool)
+	min, max := h.cellAt(r.Min.X, r.Min.Y), h.cellAt(r.Max.X, r.Max.Y)
+	for y := min.y; y <= max.y; y++ {
+		for x := min.x; x <= max.x; x++ {
+			for _, s := range h.cells[cell{x: x, y: y}] {
+				if !seen[s] {
+					seen[s] = true
+					if h.entries[s].bounds.Overlaps(r) {
+						result = append(result, s)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// compute the extent of the occupied cells from the sprites
+func (h *SpatialHash) updateExtent() {
+	first := true
+	for _, e := range h.entries {
+		if first {
+			h.extentMin, h.extentMax = e.min, e.max
+			first = false
+			continue
+		}
+		h.extentMin = cell{x: minInt(h.extentMin.x, e.min.x), y: minInt(h.extentMin.y, e.min.y)}
+		h.extentMax = cell{x: maxInt(h.extentMax.x, e.max.x), y: maxInt(h.extentMax.y, e.max.y)}
+	}
+	h.extentDirty = false
+}
+
+//Nearest returns the sprite whose bounds are the closest to the point, searching the cells in growing rings
+func (h *SpatialHash) Nearest(x, y float64) *Sprite {
+	if len(h.entries) == 0 {
+		return nil
+	}
+
+	// extent of the occupied cells, to stop the search
+	if h.extentDirty {
+		h.updateExtent()
+	}
+	center := h.cellAt(x, y)
+	maxRing := maxInt(maxInt(center.x-h.extentMin.x, h.extentMax.x-center.x), maxInt(center.y-h.extentMin.y, h.extentMax.y-center.y))
+
+	var nearest *Sprite
+	best := math.Inf(1)
+	for ring := 0; ring <= maxRing; ring++ {
+		// the cells of this ring are all farther than the best sprite
+		if nearest != nil && float64(ring-1)*h.CellSize > best {
+			break
+		}
+		h.eachRingCell(center, ring, func(c cell) {
+			for _, s := range h.cells[c] {
+				if d := rectDistance(h.entries[s].bounds, x, y); d < best {
+					best = d
+					nearest = s
+				}
+			}
+		})
+	}
+	return nearest
+}
+
+// calls the function for each cell on the perimeter of the square ring around the center
+func (h *SpatialHash) eachRingCell(center cell, ring int, f func(c cell)) {
+	if ring == 0 {
+		f(center)
+		return
+	}
+	// top and bottom rows, corners included
+	for cx := center.x - ring; cx <= center.x+ring; cx++ {
+		f(cell{x: cx, y: center.y - ring})
+		f(cell{x: cx, y: center.y + ring})
+	}
+	// left and right columns, without the corners
+	for cy := center.y - ring + 1; cy < center.y+ring; cy++ {
+		f(cell{x: center.x - ring, y: cy})
+		f(cell{x: center.x + ring, y: cy})
+	}
+}
+
+//Pairs calls the function for each pair of sprites whose bounds overlap, once per pair
+func (h *SpatialHash) Pairs(f func(a, b *Sprite)) {
+	type pair struct{ a, b *Sprite }
+	seen := make(map[pair]bool)
+	for _, sprites := range h.cells {
+		for i := 0; i < len(sprites); i++ {
+			for j := i + 1; j < len(sprites); j++ {
+				a, b := sprites[i], sprites[j]
+				if seen[pair{a, b}] || seen[pair{b, a}] {
+					continue
+				}
+				seen[pair{a, b}] = true
+				if h.entries[a].bounds.Overlaps(h.entries[b].bounds) {
+					f(a, b)
+				}
+			}
+		}
+	}
+}
+
+//////////////////////////////////////////// QUADTREE ////////////////////////////////////////////
+
+type quadNode struct {
+	rect     Rect
+	depth    int
+	sprites  []*Sprite
+	parent   *quadNode
+	children []*quadNode
+}
+
+type quadEntry struct {
+	bounds Rect
+	node   *quadNode
+}
+
+//Quadtree divides the world in four recursively, good for sprites of very different sizes, the divisions are merged back when their sprites are removed
+type Quadtree struct {
+	// Number of sprites in a node before it is divided
+	MaxSprites int
+
+	// Maximum number of divisions
+	MaxDepth int
+
+	root    *quadNode
+	entries map[*Sprite]*quadEntry
+}
+
+/*
+NewQuadtree creates a quadtree covering the world, sprites outside of the world are kept at the top of the tree
+
+Example :
+
+tree := sprite.NewQuadtree(sprite.NewRect(0, 0, 2048, 2048))
+*/
+func NewQuadtree(world Rect) *Quadtree {
+	t := new(Quadtree)
+	t.MaxSprites = 8
+	t.MaxDepth = 8
+	t.root = &quadNode{rect: world}
+	t.entries = make(map[*Sprite]*quadEntry)
+	return t
+}
+
+func containsRect(outer, inner Rect) bool {
+	return inner.Min.X >= outer.Min.X && inner.Max.X <= outer.Max.X && inner.Min.Y >= outer.Min.Y && inner.Max.Y <= outer.Max.Y
+}
+
+// child of the node which contains the rectangle, nil if none
+func (n *quadNode) childFor(r Rect) *quadNode {
+	for _, child := range n.children {
+		if containsRect(child.rect, r) {
+			return child
+		}
+	}
+	return nil
+}
+
+func (n *quadNode) split() {
+	w, h := n.rect.Width()/2, n.rect.Height()/2
+	x, y := n.rect.Min.X, n.rect.Min.Y
+	n.children = []*quadNode{
+		{rect: NewRect(x, y, w, h), depth: n.depth + 1, parent: n},
+		{rect: NewRect(x+w, y, w, h), depth: n.depth + 1, parent: n},
+		{rect: NewRect(x, y+h, w, h), depth: n.depth + 1, parent: n},
+		{rect: NewRect(x+w, y+h, w, h), depth: n.depth + 1, parent: n},
+	}
+}
+
+// move up the sprites of the divisions which do not need to be divided anymore, from the node to the root
+func (t *Quadtree) merge(n *quadNode) {
+	if n.children == nil {
+		n = n.parent
+	}
+	for ; n != nil; n = n.parent {
+		count := len(n.sprites)
+		for _, child := range n.children {
+			if child.children != nil {
+				return
+			}
+			count += len(child.sprites)
+		}
+		if count > t.MaxSprites {
+			return
+		}
+		for _, child := range n.children {
+			for _, s := range child.sprites {
+				n.sprites = append(n.sprites, s)
+				t.entries[s].node = n
+			}
+		}
+		n.children = nil
+	}
+}
+
+func (t *Quadtree) insert(n *quadNode, s *Sprite, e *quadEntry) {
+	for n.children != nil {
+		child := n.childFor(e.bounds)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+
+	n.sprites = append(n.sprites, s)
+	e.node = n
+
+	// divide the node and move down its sprites
+	if n.children == nil && len(n.sprites) > t.MaxSprites && n.depth < t.MaxDepth {
+		n.split()
+		sprites := n.sprites
+		n.sprites = nil
+		for _, other := range sprites {
+			t.insert(n, other, t.entries[other])
+		}
+	}
+}
+
+func (n *quadNode) remove(s *Sprite) {
+	for i, other := range n.sprites {
+		if other == s {
+			n.sprites = append(n.sprites[:i], n.sprites[i+1:]...)
+			return
+		}
+	}
+}
+
+//Insert adds a sprite to the tree
+func (t *Quadtree) Insert(s *Sprite) {
+	if _, ok := t.entries[s]; ok {
+		t.Update(s)
+		return
+	}
+	e := &quadEntry{bounds: s.broadBounds()}
+	t.entries[s] = e
+	t.insert(t.root, s, e)
+}
+
+//Remove removes a sprite from the tree
+func (t *Quadtree) Remove(s *Sprite) {
+	if e, ok := t.entries[s]; ok {
+		e.node.remove(s)
+		delete(t.entries, s)
+		t.merge(e.node)
+	}
+}
+
+//Update moves a sprite in the tree, only if it does not fit its node anymore
+func (t *Quadtree) Update(s *Sprite) {
+	e, ok := t.entries[s]
+	if !ok {
+		t.Insert(s)
+		return
+	}
+	e.bounds = s.broadBounds()
+
+	n := e.node
+	fits := n == t.root || containsRect(n.rect, e.bounds)
+	if fits && (n.children == nil || n.childFor(e.bounds) == nil) {
+		return // still in the right node
+	}
+	n.remove(s)
+	t.insert(t.root, s, e)
+	t.merge(n)
+}
+
+//UpdateAll updates all the sprites of the tree
+func (t *Quadtree) UpdateAll() {
+	for s := range t.entries {
+		t.Update(s)
+	}
+}
+
+//Query returns the sprites whose bounds overlap the rectangle
+func (t *Quadtree) Query(r Rect) []*Sprite {
+	var result []*Sprite
+	var query func(n *quadNode)
+	query = func(n *quadNode) {
+		for _, s := range n.sprites {
+			if t.entries[s].bounds.Overlaps(r) {
+				result = append(result, s)
+			}
+		}
+		for _, child := range n.children {
+			if child.rect.Overlaps(r) {
+				query(child)
+			}
+		}
+	}
+	query(t.root)
+	return result
+}
+
+//Nearest returns the sprite whose bounds are the closest to the point
+func (t *Quadtree) Nearest(x, y float64) *Sprite {
+	var nearest *Sprite
+	best := math.Inf(1)
+
+	var search func(n *quadNode)
+	search = func(n *quadNode) {
+		for _, s := range n.sprites {
+			if d := rectDistance(t.entries[s].bounds, x, y); d < best {
+				best = d
+				nearest = s
+			}
+		}
+
+		// closest children first, skip the ones farther than the best sprite
+		children := append([]*quadNode(nil), n.children...)
+		for i := 1; i < len(children); i++ {
+			for j := i; j > 0 && rectDistance(children[j].rect, x, y) < rectDistance(children[j-1].rect, x, y); j-- {
+				children[j], children[j-1] = children[j-1], children[j]
+			}
+		}
+		for _, child := range children {
+			if rectDistance(child.rect, x, y) <= best {
+				search(child)
+			}
+		}
+	}
+	search(t.root)
+	return nearest
+}
+
+//Pairs calls the function for each pair of sprites whose bounds overlap, once per pair
+func (t *Quadtree) Pairs(f func(a, b *Sprite)) {
+	var pairs func(n *quadNode, ancestors []*Sprite)
+	pairs = func(n *quadNode, ancestors []*Sprite) {
+		for i, a := range n.sprites {
+			ba := t.entries[a].bounds
+			for _, b := range n.sprites[i+1:] {
+				if ba.Overlaps(t.entries[b].bounds) {
+					f(a, b)
+				}
+			}
+			for _, b := range ancestors {
+				if ba.Overlaps(t.entries[b].bounds) {
+					f(b, a)
+				}
+			}
+		}
+		if len(n.children) > 0 {
+			ancestors = append(ancestors[:len(ancestors):len(ancestors)], n.sprites...)
+			for _, child := range n.children {
+				pairs(child, ancestors)
+			}
+		}
+	}
+	pairs(t.root, nil)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
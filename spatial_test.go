@@ -0,0 +1,173 @@
+package sprite
+
+import (
+	"sort"
+	"testing"
+)
+
+func spatialIndexes() map[string]SpatialIndex {
+	return map[string]SpatialIndex{
+		"hash":     NewSpatialHash(32),
+		"quadtree": NewQuadtree(NewRect(0, 0, 512, 512)),
+	}
+}
+
+func TestSpatialQueryAndNearest(t *testing.T) {
+	for name, index := range spatialIndexes() {
+		a := newTestSprite(10, 10, 10, 10)
+		b := newTestSprite(100, 100, 10, 10)
+		c := newTestSprite(400, 50, 40, 40)
+		for _, s := range []*Sprite{a, b, c} {
+			index.Insert(s)
+		}
+
+		if found := index.Query(NewRect(0, 0, 50, 50)); len(found) != 1 || found[0] != a {
+			t.Errorf("%s : query found %v", name, found)
+		}
+		if found := index.Query(NewRect(90, 40, 400, 80)); len(found) != 2 {
+			t.Errorf("%s : query found %d sprites, want 2", name, len(found))
+		}
+		if nearest := index.Nearest(350, 60); nearest != c {
+			t.Errorf("%s : nearest is not c", name)
+		}
+
+		// moved far away then removed : the search stops at the sprites left
+		c.Position(5000, 5000)
+		index.Update(c)
+		if nearest := index.Nearest(4000, 4000); nearest != c {
+			t.Errorf("%s : nearest is not the moved sprite", name)
+		}
+		index.Remove(c)
+		if nearest := index.Nearest(4000, 4000); nearest != b {
+			t.Errorf("%s : nearest is not b after the removal", name)
+		}
+		index.Remove(a)
+		index.Remove(b)
+		if nearest := index.Nearest(0, 0); nearest != nil {
+			t.Errorf("%s : nearest in an empty index", name)
+		}
+	}
+}
+
+func TestSpatialPairs(t *testing.T) {
+	for name, index := range spatialIndexes() {
+		var sprites []*Sprite
+		for i := 0; i < 30; i++ {
+			s := newTestSprite(float64(i*15), float64(i%3*200), 20, 20)
+			sprites = append(sprites, s)
+			index.Insert(s)
+		}
+		big := newTestSprite(0, 0, 100, 10)
+		index.Insert(big)
+		sprites = append(sprites, big)
+
+		var want, got []string
+		for i, a := range sprites {
+			for _, b := range sprites[i+1:] {
+				if a.Bounds().Overlaps(b.Bounds()) {
+					want = append(want, pairKey(a, b, sprites))
+				}
+			}
+		}
+		index.Pairs(func(a, b *Sprite) {
+			got = append(got, pairKey(a, b, sprites))
+		})
+		sort.Strings(want)
+		sort.Strings(got)
+		if len(got) != len(want) {
+			t.Fatalf("%s : %d pairs, want %d", name, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s : pair %s, want %s", name, got[i], want[i])
+			}
+		}
+	}
+}
+
+// name of a pair, independent of its order
+func pairKey(a, b *Sprite, sprites []*Sprite) string {
+	i, j := 0, 0
+	for k, s := range sprites {
+		if s == a {
+			i = k
+		}
+		if s == b {
+			j = k
+		}
+	}
+	if i > j {
+		i, j = j, i
+	}
+	return string(rune('A'+i)) + string(rune('A'+j))
+}
+
+func TestQuadtreeMerge(t *testing.T) {
+	tree := NewQuadtree(NewRect(0, 0, 512, 512))
+	tree.MaxSprites = 2
+	var sprites []*Sprite
+	for i := 0; i < 6; i++ {
+		s := newTestSprite(float64(i*80), float64(i*80), 10, 10)
+		sprites = append(sprites, s)
+		tree.Insert(s)
+	}
+	if tree.root.children == nil {
+		t.Fatal("the root is not divided")
+	}
+
+	for _, s := range sprites[2:] {
+		tree.Remove(s)
+	}
+	if tree.root.children != nil {
+		t.Error("the divisions are not merged")
+	}
+	for _, s := range sprites[:2] {
+		if tree.entries[s].node != tree.root {
+			t.Error("a sprite is not moved up to the root")
+		}
+	}
+	if found := tree.Query(NewRect(0, 0, 512, 512)); len(found) != 2 {
+		t.Errorf("query found %d sprites after the merge, want 2", len(found))
+	}
+}
+
+func TestSpatialHashExtent(t *testing.T) {
+	h := NewSpatialHash(10)
+	a := newTestSprite(0, 0, 5, 5)
+	b := newTestSprite(95, 45, 5, 5)
+	h.Insert(a)
+	h.Insert(b)
+	if h.extentMin != (cell{0, 0}) || h.extentMax != (cell{10, 5}) {
+		t.Errorf("extent %v %v", h.extentMin, h.extentMax)
+	}
+	h.Remove(b)
+	h.Nearest(0, 0)
+	if h.extentMax != (cell{0, 0}) {
+		t.Errorf("extent %v after the removal, want {0 0}", h.extentMax)
+	}
+}
+
+func TestSpatialHashRing(t *testing.T) {
+	h := NewSpatialHash(10)
+	center := cell{x: 3, y: -2}
+	for ring := 0; ring <= 3; ring++ {
+		seen := make(map[cell]int)
+		h.eachRingCell(center, ring, func(c cell) { seen[c]++ })
+		want := 1
+		if ring > 0 {
+			want = 8 * ring
+		}
+		if len(seen) != want {
+			t.Errorf("ring %d : %d cells, want %d", ring, len(seen), want)
+		}
+		for c, n := range seen {
+			dx, dy := c.x-center.x, c.y-center.y
+			if maxInt(maxInt(dx, -dx), maxInt(dy, -dy)) != ring {
+				t.Errorf("ring %d : cell %v is not on the ring", ring, c)
+			}
+			if n != 1 {
+				t.Errorf("ring %d : cell %v visited %d times", ring, c, n)
+			}
+		}
+	}
+}
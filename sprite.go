@@ -19,6 +19,8 @@ import (
 	"image/color"
 	"log"
 	"math"
+	"math/rand"
+	"sync"
 	"time"
 	//"fmt"
 )
@@ -45,6 +47,36 @@ const (
 	// Move absoluty the sprite
 	Move
 
+	// Shake the position of the sprite
+	Shake
+
+	// Blink the sprite (toggle its display)
+	Blink
+
+	// Pulse the zoom or the transparency of the sprite
+	Pulse
+
+	// Tint the sprite toward a color (fully without To or By)
+	Tint
+
+	// Change the saturation of the sprite (to -1, grayscale, without To or By)
+	Saturate
+
+	// Change the brightness of the sprite (to 1, white, without To or By)
+	Brighten
+
+	// Change the contrast of the sprite (to 1, twice the contrast, without To or By)
+	Contrast
+
+	// Rotate the hue of the sprite around the colour wheel
+	RotateHue
+
+	// Invert the colours of the sprite
+	Invert
+
+	// Move the sprite along a path
+	FollowPath
+
 	// For Flip effect
 	Horizontaly = false
 	Verticaly   = true
@@ -66,12 +98,33 @@ type Sprite struct {
 	// Y coordinate of the sprite (in pixel)
 	Y float64
 
-	// Speed is in pixel/frame
+	// Speed is in pixel/frame, it follows the velocity
 	Speed float64
 
-	// Direction is an Angle in degres
+	// Direction is an Angle in degres, it follows the velocity
 	Direction float64
 
+	// Velocity in pixel/second (the Y axis goes down)
+	VelocityX, VelocityY float64
+
+	// Acceleration in pixel/second²
+	AccelerationX, AccelerationY float64
+
+	// Gravity in pixel/second², added to the vertical acceleration
+	Gravity float64
+
+	// Part of the velocity lost per second (from 0 to 1)
+	Drag float64
+
+	// Deceleration in pixel/second² opposed to the velocity
+	Friction float64
+
+	// Maximum speed in pixel/second (0 is unlimited)
+	MaxSpeed float64
+
+	// Angular velocity in degres/second, added to Angle
+	AngularVelocity float64
+
 	// Zoom in or out on X axis
 	ZoomX float64
 
@@ -84,6 +137,27 @@ type Sprite struct {
 	// Transparency
 	Alpha float64
 
+	// Color to tint the sprite toward (nil is no tint)
+	Tint color.Color
+
+	// Strength of the tint from 0 (none) to 1 (plain color)
+	TintStrength float64
+
+	// Saturation added to the colours (-1 is grayscale, 0 is unchanged)
+	Saturation float64
+
+	// Brightness added to the colors (from -1 to 1, 0 is unchanged)
+	Brightness float64
+
+	// Contrast added to the colours (-1 is plain gray, 0 is unchanged)
+	Contrast float64
+
+	// Rotation of the hue in degres
+	HueRotation float64
+
+	// Inversion of the colors from 0 (none) to 1 (negative)
+	Inversion float64
+
 	// Angle of rotation in degres
 	Angle float64
 
@@ -93,17 +167,65 @@ type Sprite struct {
 	// Skew on Y axis in degres
 	SkewY float64
 
-	// Visibility of the sprite
+	// Order of drawing inside a group, sprites with a higher Z are drawn over the others
+	Z float64
+
+	// Visibility of the sprite, a child is also hidden while its parent is hidden
 	Visible bool
 
 	// Animated or not
 	Animated bool
 
-	// Displace X and Y coordonnate to the center of the sprite
+	// Displace X and Y coordonnate to the center of the sprite (the same as an anchor at 0.5, 0.5)
 	CenterCoordonnates bool
 
+	// Anchor of X and Y coordonnates and pivot of zoom and rotation, from 0 (left, top) to 1 (right, bottom)
+	AnchorX, AnchorY float64
+
 	// Draw debug borders around sprite
 	Borders bool
+
+	// Rectangle and behaviour to keep the sprite inside the world (nil is none)
+	WorldBounds *WorldBounds
+
+	// Collision= CollisionAABB, CollisionOBB, CollisionPixel, CollisionShapes
+	Collision int
+
+	// Collision layers the sprite belongs to (bit field)
+	CollisionLayer uint32
+
+	// Collision layers the sprite interacts with (bit field)
+	CollisionMask uint32
+
+	// Offset of the Shake effects
+	shakeX, shakeY float64
+
+	// Hidden by a Blink effect
+	blinkHidden bool
+
+	// Speed and Direction after the last update, to detect changes
+	lastSpeed, lastDirection float64
+
+	// Time of the last update
+	lastUpdate time.Time
+
+	// Update was called since the last Draw
+	updated bool
+
+	// Sprite which this sprite is relative to, and sprites relative to this one
+	parent   *Sprite
+	children []*Sprite
+
+	// Pool the sprite returns to when it is released (nil is none)
+	pool *Pool
+
+	// Functions registered from other goroutines, called by the game loop
+	mutex   sync.Mutex
+	pending []func()
+
+	// Animations waiting to be played, and the one currently played from the queue
+	queue   []*queuedAnimation
+	playing *queuedAnimation
 }
 
 /*Animation contains animations and effects */
@@ -130,6 +252,30 @@ type Animation struct {
 	// Height of the animation steps (in pixel)
 	StepHeight int
 
+	// Rectangles of the steps inside the image, used instead of the steps on one line (for tilesets)
+	Frames []image.Rectangle
+
+	// Duration of each step, used instead of the same duration for all the steps
+	StepDurations []time.Duration
+
+	// Rectangle used for collisions inside a step (nil is the whole step)
+	Hitbox *Rect
+
+	// Pivot of the steps (in pixel), used instead of the anchor of the sprite (nil is none)
+	Pivot *Point
+
+	// Pivot of some steps (in pixel), used instead of the pivot of the animation
+	StepPivots map[int]Point
+
+	// Collision masks of the steps, generated from the alpha channel
+	Masks []*Mask
+
+	// Decoded image of the animation, to build the masks again (see SetAlphaThreshold)
+	source image.Image
+
+	// Collision shapes (hitboxes and hurtboxes)
+	Shapes []*FrameShape
+
 	// Total duration of the animation in millisecond
 	Duration time.Duration
 
@@ -139,25 +285,39 @@ type Animation struct {
 	// Effects object
 	Effects []*animationEffect
 
+	// Keyframe tracks
+	Tracks []*animationTrack
+
 	// Animation once and disapared
 	RunOnce bool
 
 	// Callback after run once
 	callbackAfterRunOnce func(*Sprite)
 
+	// Completion of the run once
+	runOnceHandle *Handle
+
 	// Start time of the current step
 	currentStepTimeStart time.Time
 }
 
 type animationEffect struct {
 	options                                     *EffectOptions
-	zoomStart                                   float64
+	zoomStart, zoomEnd, zoomStartY              float64
 	redStart, greenStart, blueStart, alphaStart float64
-	angleStart                                  float64
+	redEnd, greenEnd, blueEnd                   float64
+	alphaFrom, alphaEnd                         float64
+	angleStart, angleEnd                        float64
 	xStart, yStart                              float64
+	xEnd, yEnd                                  float64
+	shakeDX, shakeDY                            float64
+	shakeNext                                   time.Time
+	value                                       *float64
+	valueStart, valueEnd                        float64
 	duration                                    time.Duration
 	timeStart, timeEnd                          time.Time
 	repeatCallback                              func()
+	started, finished                           bool
 }
 
 //EffectOptions contains options for the effect
@@ -165,9 +325,12 @@ type EffectOptions struct {
 	// Name of animation (default is omitted)
 	Animation string
 
-	// Effect= Zoom, FlipX, FlipY, Fade, Turn, Move
+	// Effect= Zoom, Flip, Fade, Turn, Hue, Move, Shake, Blink, Pulse, Tint, Saturate, Brighten, Contrast, RotateHue, Invert, FollowPath
 	Effect int
 
+	// For Tint effect
+	Color color.Color
+
 	// For Fade and FadeINOUT effects
 	FadeFrom, FadeTo float64
 
@@ -183,12 +346,42 @@ type EffectOptions struct {
 	// Horizontaly or Verticaly
 	Axis bool
 
-	// For Hue effect
+	// For Hue effect, a zero channel is unchanged (1), use To or By to target 0
 	Red, Green, Blue float64
 
 	// For Move effect
 	X, Y float64
 
+	// For Zoom, Fade, Turn, Hue (all the channels) and colour effects, absolute target (optional, zero is a valid value)
+	To *float64
+
+	// For Zoom, Fade, Turn, Hue (all the channels) and colour effects, target relative to the value at the start of the effect (optional)
+	By *float64
+
+	// For Move effect, absolute target coordinates (optional, zero is a valid value)
+	ToX, ToY *float64
+
+	// For Move effect, target relative to the position at the start of the effect (optional)
+	ByX, ByY *float64
+
+	// For FollowPath effect
+	Path *Path
+
+	// For FollowPath effect, rotate the sprite to face the direction of the path
+	OrientToPath bool
+
+	// For Shake effect (in pixel) and Pulse effect (zoom or alpha variation)
+	Amplitude float64
+
+	// For Shake, Blink and Pulse effects, number of shakes, blinks or pulses per second
+	Frequency float64
+
+	// For Shake effect, from 0 (constant amplitude) to 1 (amplitude reaches 0 at the end of the effect)
+	Decay float64
+
+	// For Pulse effect, pulse the transparency instead of the zoom
+	PulseAlpha bool
+
 	// Duration of the effect
 	Duration int
 
@@ -198,12 +391,31 @@ type EffectOptions struct {
 	// Redo the animation on the counter way
 	GoBack bool
 
+	// With GoBack, number of go and return in one run of the effect, sharing its duration (default is 1)
+	YoyoCount int
+
 	// Repeat or not at the end of effect
 	Repeat bool
 
+	// Number of repetitions after the first run, with GoBack one repetition is a go and return (default is infinite with Repeat).
+	// A non-zero count repeats the effect even without Repeat
+	RepeatCount int
+
+	// Delay before the start of the effect (in millisecond)
+	Delay int
+
+	// Delay between two repetitions (in millisecond)
+	RepeatDelay int
+
 	// function to launch afert one complete effect
 	Callback func()
 
+	// function to launch when the effect repeats, with the number of the repetition (starting at 1)
+	OnRepeat func(iteration int)
+
+	// Completion of the effect
+	handle *Handle
+
 	// index of the effect in the stack
 	index int
 
@@ -226,16 +438,27 @@ func NewSprite() *Sprite {
 	sprite.Green = 1
 	sprite.Blue = 1
 	sprite.Alpha = 1
+	sprite.CollisionLayer = 1
+	sprite.CollisionMask = LayerAll
 	return sprite
 }
 
 func newAnimation(path string, duration int, steps int, filter ebiten.Filter) *Animation {
+	animation, err := newAnimationFromFile(path, duration, steps, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return animation
+}
+
+func newAnimationFromFile(path string, duration int, steps int, filter ebiten.Filter) (*Animation, error) {
 	var err error
+	var source image.Image
 	animation := new(Animation)
 	animation.Path = path
-	animation.Image, _, err = ebitenutil.NewImageFromFile(path, filter)
+	animation.Image, source, err = ebitenutil.NewImageFromFile(path, filter)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	animation.Steps = steps
 	animation.Duration = time.Millisecond * time.Duration(duration)
@@ -248,8 +471,12 @@ func newAnimation(path string, duration int, steps int, filter ebiten.Filter) *A
 	animation.OneStepDuration = time.Duration(int(animation.Duration) / animation.Steps)
 
 	animation.Effects = make([]*animationEffect, 0)
+	animation.Tracks = make([]*animationTrack, 0)
 
-	return animation
+	animation.source = source
+	animation.Masks = newMasks(source, animation, AlphaThreshold)
+
+	return animation, nil
 }
 
 //////////////////////////////////////////// METHODS ////////////////////////////////////////////
@@ -282,11 +509,24 @@ Example :
 
 sprites[i].AddEffect(&sprite.EffectOptions{ Effect: sprite.Zoom, Zoom:3, Duration:2000, Repeat:true, GoBack:true })
 
+The returned handle is done when the effect is over, it must be called from the game loop (see Effect for other goroutines)
+
+The options can be used again once the effect is over, each call returns a new handle
 */
-func (sprite *Sprite) AddEffect(options *EffectOptions) {
+func (sprite *Sprite) AddEffect(options *EffectOptions) *Handle {
+	return sprite.addEffect(options, newHandle())
+}
+
+func (sprite *Sprite) addEffect(options *EffectOptions, h *Handle) *Handle {
 	if options.Animation == "" {
 		options.Animation = "default"
 	}
+	options.handle = h
+	options.loopCounter = 0 // a new effect, not a repetition
+	if _, ok := sprite.Animations[options.Animation]; !ok { // nothing to animate
+		h.close()
+		return h
+	}
 
 	options.durationTime = time.Millisecond * time.Duration(options.Duration)
 
@@ -301,51 +541,43 @@ func (sprite *Sprite) AddEffect(options *EffectOptions) {
 		sprite.turn(options)
 	case Hue:
 		sprite.hue(options)
-	case Move:
+	case Move, FollowPath:
 		sprite.move(options)
+	case Shake, Blink:
+		sprite.juice(options)
+	case Pulse:
+		sprite.pulse(options)
+	case Tint, Saturate, Brighten, Contrast, RotateHue, Invert:
+		sprite.colorize(options)
+	default: // unknown effect
+		h.close()
 	}
+
+	return h
 }
 
-func (sprite *Sprite) zoom(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
-	e.zoomStart = sprite.ZoomX
+// the effect runs again at its end
+func (options *EffectOptions) repeats() bool {
+	return options.Repeat || options.RepeatCount > 0
+}
 
-	if options.loopCounter == 0 { // first loop
-		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
-		options.index = len(sprite.Animations[options.Animation].Effects) - 1 // store index
-	} else {
-		sprite.Animations[options.Animation].Effects[options.index] = e
-	}
+func (sprite *Sprite) zoom(options *EffectOptions) {
+	e := sprite.stackEffect(options)
 
-	if options.Repeat == true {
+	if options.repeats() {
 		e.repeatCallback = func() {
 			sprite.ZoomX = e.zoomStart // reset zoom
-			e = nil                    // erase previous effect
+			sprite.ZoomY = e.zoomStart
+			e = nil // erase previous effect
 			sprite.zoom(options)
 		}
 	}
-
-	options.loopCounter++
 }
 
 func (sprite *Sprite) flip(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
-	if e.options.Axis == Horizontaly {
-		e.zoomStart = sprite.ZoomX
-	} else {
-		e.zoomStart = sprite.ZoomY
-	}
+	e := sprite.stackEffect(options)
 
-	if options.loopCounter == 0 { // first loop
-		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
-		options.index = len(sprite.Animations[options.Animation].Effects) - 1 // store index
-	} else {
-		sprite.Animations[options.Animation].Effects[options.index] = e
-	}
-
-	if options.Repeat == true {
+	if options.repeats() {
 		e.repeatCallback = func() {
 			if e.options.Axis == Horizontaly {
 				sprite.ZoomX = e.zoomStart // reset zoom
@@ -356,85 +588,37 @@ func (sprite *Sprite) flip(options *EffectOptions) {
 			sprite.flip(options)
 		}
 	}
-
-	options.loopCounter++
 }
 
 func (sprite *Sprite) fade(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
-	e.alphaStart = sprite.Alpha
+	e := sprite.stackEffect(options)
 
-	if options.loopCounter == 0 { // first loop
-		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
-		options.index = len(sprite.Animations[options.Animation].Effects) - 1 // store index
-	} else {
-		sprite.Animations[options.Animation].Effects[options.index] = e
-	}
-
-	if options.Repeat == true {
+	if options.repeats() {
 		e.repeatCallback = func() {
 			sprite.Alpha = e.alphaStart // reset alpha
 			e = nil                     // erase previous effect
 			sprite.fade(options)
 		}
 	}
-
-	options.loopCounter++
 }
 
 func (sprite *Sprite) turn(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
-	e.angleStart = sprite.Angle
+	e := sprite.stackEffect(options)
 
-	if options.loopCounter == 0 { // first loop
-		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
-		options.index = len(sprite.Animations[options.Animation].Effects) - 1 // store index
-	} else {
-		sprite.Animations[options.Animation].Effects[options.index] = e
-	}
-
-	if options.Repeat == true {
+	if options.repeats() {
 		e.repeatCallback = func() {
-			sprite.Angle = e.angleStart // reset alpha
+			sprite.Angle = e.angleStart // reset angle
 			e = nil                     // erase previous effect
 			sprite.turn(options)
 		}
 	}
-
-	options.loopCounter++
 }
 
 func (sprite *Sprite) hue(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
-
-	// init value
-	if e.options.Red == 0 {
-		e.options.Red = 1
-	}
-	if e.options.Green == 0 {
-		e.options.Green = 1
-	}
-	if e.options.Blue == 0 {
-		e.options.Blue = 1
-	}
-
-	e.redStart = sprite.Red
-	e.greenStart = sprite.Green
-	e.blueStart = sprite.Blue
-
-	if options.loopCounter == 0 { // first loop
-		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
-		options.index = len(sprite.Animations[options.Animation].Effects) - 1 // store index
-	} else {
-		sprite.Animations[options.Animation].Effects[options.index] = e
-	}
+	e := sprite.stackEffect(options)
 
-	if options.Repeat == true {
+	if options.repeats() {
 		e.repeatCallback = func() {
-			//fmt.Printf("DEBUG z.redStart:%f  z.greenStart:%f  z.blueStart:%f\n", e.redStart, e.greenStart, e.blueStart)
 			sprite.Red = e.redStart
 			sprite.Green = e.greenStart
 			sprite.Blue = e.blueStart
@@ -442,23 +626,68 @@ func (sprite *Sprite) hue(options *EffectOptions) {
 			sprite.hue(options)
 		}
 	}
-
-	options.loopCounter++
 }
 
 func (sprite *Sprite) move(options *EffectOptions) {
-	e := new(animationEffect)
-	e.options = options
+	e := sprite.stackEffect(options)
 
-	if e.options.X == 0 {
-		e.options.X = sprite.X
+	if options.repeats() {
+		e.repeatCallback = func() {
+			if options.Effect == FollowPath && options.Path != nil { // the next loop starts at the start of the path
+				position, angle := options.Path.At(0)
+				sprite.X = position.X
+				sprite.Y = position.Y
+				if options.OrientToPath {
+					sprite.Angle = angle
+				}
+			} else {
+				sprite.X = e.xStart // reset x position
+				sprite.Y = e.yStart // reset y position
+			}
+			e = nil // erase previous effect
+			sprite.move(options)
+		}
 	}
-	if e.options.Y == 0 {
-		e.options.Y = sprite.Y
+}
+
+func (sprite *Sprite) juice(options *EffectOptions) {
+	e := sprite.stackEffect(options)
+
+	if options.repeats() {
+		e.repeatCallback = func() {
+			e = nil // erase previous effect
+			sprite.juice(options)
+		}
+	}
+}
+
+func (sprite *Sprite) pulse(options *EffectOptions) {
+	e := sprite.stackEffect(options)
+
+	if options.repeats() {
+		e.repeatCallback = func() {
+			e = nil // erase previous effect
+			sprite.pulse(options)
+		}
+	}
+}
+
+func (sprite *Sprite) colorize(options *EffectOptions) {
+	e := sprite.stackEffect(options)
+
+	if options.repeats() {
+		e.repeatCallback = func() {
+			*e.value = e.valueStart // reset the colour property
+			e = nil                 // erase previous effect
+			sprite.colorize(options)
+		}
 	}
+}
 
-	e.xStart = sprite.X
-	e.yStart = sprite.Y
+// push a new effect in the stack of the animation, or replace the previous loop of the same effect
+func (sprite *Sprite) stackEffect(options *EffectOptions) *animationEffect {
+	e := new(animationEffect)
+	e.options = options
 
 	if options.loopCounter == 0 { // first loop
 		sprite.Animations[options.Animation].Effects = append(sprite.Animations[options.Animation].Effects, e)
@@ -467,16 +696,153 @@ func (sprite *Sprite) move(options *EffectOptions) {
 		sprite.Animations[options.Animation].Effects[options.index] = e
 	}
 
-	if options.Repeat == true {
-		e.repeatCallback = func() {
-			sprite.X = e.xStart // reset x position
-			sprite.Y = e.yStart // reset y position
-			e = nil             // erase previous effect
-			sprite.move(options)
+	options.loopCounter++
+	return e
+}
+
+// capture the values of the sprite when the effect starts and compute the targets
+func (sprite *Sprite) startEffect(e *animationEffect) {
+	options := e.options
+
+	switch options.Effect {
+	case Zoom:
+		e.zoomStart = sprite.ZoomX
+		e.zoomEnd = effectTarget(e.zoomStart, options.To, options.By, options.Zoom)
+
+	case Flip:
+		if options.Axis == Horizontaly {
+			e.zoomStart = sprite.ZoomX
+		} else {
+			e.zoomStart = sprite.ZoomY
+		}
+
+	case Fade:
+		e.alphaStart = sprite.Alpha
+		if options.To != nil || options.By != nil { // from the current alpha
+			e.alphaFrom = e.alphaStart
+			e.alphaEnd = effectTarget(e.alphaStart, options.To, options.By, 0)
+		} else {
+			e.alphaFrom = options.FadeFrom
+			e.alphaEnd = options.FadeTo
+		}
+
+	case Turn:
+		clockwise := 1.0
+		if options.Clockwise {
+			clockwise = -1.0
+		}
+		e.angleStart = sprite.Angle
+		e.angleEnd = effectTarget(e.angleStart, options.To, options.By, options.Angle*clockwise)
+
+	case Hue:
+		e.redStart = sprite.Red
+		e.greenStart = sprite.Green
+		e.blueStart = sprite.Blue
+		e.redEnd = effectTarget(e.redStart, options.To, options.By, hueChannel(options.Red))
+		e.greenEnd = effectTarget(e.greenStart, options.To, options.By, hueChannel(options.Green))
+		e.blueEnd = effectTarget(e.blueStart, options.To, options.By, hueChannel(options.Blue))
+
+	case Move:
+		e.xStart = sprite.X
+		e.yStart = sprite.Y
+
+		// a zero legacy coordinate keeps the current one
+		x, y := options.X, options.Y
+		if x == 0 {
+			x = e.xStart
+		}
+		if y == 0 {
+			y = e.yStart
+		}
+		e.xEnd = effectTarget(e.xStart, options.ToX, options.ByX, x)
+		e.yEnd = effectTarget(e.yStart, options.ToY, options.ByY, y)
+
+	case Pulse:
+		e.zoomStart = sprite.ZoomX
+		e.zoomStartY = sprite.ZoomY
+		e.alphaStart = sprite.Alpha
+
+	case FollowPath:
+		e.xStart = sprite.X
+		e.yStart = sprite.Y
+		e.angleStart = sprite.Angle
+
+	case Tint:
+		if options.Color != nil {
+			sprite.Tint = options.Color
 		}
+		e.value = &sprite.TintStrength
+		e.valueStart = sprite.TintStrength
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, 1)
+
+	case Saturate:
+		e.value = &sprite.Saturation
+		e.valueStart = sprite.Saturation
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, -1)
+
+	case Brighten:
+		e.value = &sprite.Brightness
+		e.valueStart = sprite.Brightness
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, 1)
+
+	case Contrast:
+		e.value = &sprite.Contrast
+		e.valueStart = sprite.Contrast
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, 1)
+
+	case RotateHue:
+		e.value = &sprite.HueRotation
+		e.valueStart = sprite.HueRotation
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, e.valueStart+options.Angle)
+
+	case Invert:
+		e.value = &sprite.Inversion
+		e.valueStart = sprite.Inversion
+		e.valueEnd = effectTarget(e.valueStart, options.To, options.By, 1)
 	}
+}
 
-	options.loopCounter++
+// restore the values of the sprite modified by an effect which is over
+func (sprite *Sprite) endEffect(e *animationEffect) {
+	switch e.options.Effect {
+	case Pulse:
+		if e.options.PulseAlpha {
+			sprite.Alpha = e.alphaStart
+		} else {
+			sprite.ZoomX = e.zoomStart
+			sprite.ZoomY = e.zoomStartY
+		}
+	}
+}
+
+// target of an effect : absolute (to), relative to the start value (by) or the legacy option
+func effectTarget(start float64, to, by *float64, legacy float64) float64 {
+	if to != nil {
+		return *to
+	}
+	if by != nil {
+		return start + *by
+	}
+	return legacy
+}
+
+// legacy channel of the Hue effect : zero is unchanged
+func hueChannel(value float64) float64 {
+	if value == 0 {
+		return 1
+	}
+	return value
+}
+
+/*
+Value returns a pointer to v, for the optional To and By fields of EffectOptions
+
+Example :
+
+mySprite.AddEffect(&sprite.EffectOptions{Effect: sprite.Move, ToX: sprite.Value(0), Duration: 1000})
+*/
+func Value(v float64) *float64 {
+	return &v
 }
 
 //GetWidth returns width of the current animation displayed
@@ -491,12 +857,12 @@ func (sprite *Sprite) GetHeight() float64 {
 	return float64(currentAnimation.StepHeight)
 }
 
-//Hide the sprite
+//Hide the sprite, its children are hidden with it but keep their own visibility
 func (sprite *Sprite) Hide() {
 	sprite.Visible = false
 }
 
-//Show the sprite
+//Show the sprite, its children hidden by Hide stay hidden
 func (sprite *Sprite) Show() {
 	sprite.Visible = true
 }
@@ -582,68 +948,162 @@ func (sprite *Sprite) Skew(arg ...float64) (float64, float64) {
 	return sprite.SkewX, sprite.SkewY
 }
 
-//Draw calculates new coordonnates and draw the sprite on the screen, after drawing, go to the next step of animation
+//Draw draws the sprite on the screen, after updating it if Update was not called since the last Draw
 func (sprite *Sprite) Draw(surface *ebiten.Image) {
-	if sprite.Visible {
-		currentAnimation := sprite.Animations[sprite.CurrentAnimation] // Animation object
+	sprite.prepare()
+	sprite.drawView(surface, &view{})
+}
 
-		options := &ebiten.DrawImageOptions{}
+// update the sprite if Update was not called since the last Draw
+func (sprite *Sprite) prepare() {
+	if !sprite.updated {
+		sprite.Update()
+	}
+}
 
-		// move sprite x,y
-		angleRad := sprite.Direction * math.Pi / 180 // convert degres into radians
-		sprite.Y -= sprite.Speed * math.Sin(angleRad)
-		sprite.X += sprite.Speed * math.Cos(angleRad)
+func (sprite *Sprite) order() (float64, float64) {
+	return sprite.Z, sprite.Y
+}
 
-		// apply diffrents effects
-		sprite.applyEffects(surface)
+// draw the sprite and its children, transformed by the view of its group or camera
+func (sprite *Sprite) drawView(surface *ebiten.Image, v *view) {
+	sprite.updated = false
 
-		// apply modification
-		if sprite.CenterCoordonnates {
-			options.GeoM.Translate(-float64(sprite.GetWidth())/2, -float64(sprite.GetHeight())/2)
-		}
-		options.GeoM.Scale(sprite.ZoomX, sprite.ZoomY)
-		options.GeoM.Rotate(deg2rad(sprite.Angle))
-		options.GeoM.Translate(sprite.X, sprite.Y)
+	if sprite.Visible && !v.hidden {
+		currentAnimation := sprite.Animations[sprite.CurrentAnimation] // Animation object
 
-		options.GeoM.Skew(deg2rad(sprite.SkewX), deg2rad(sprite.SkewY))
+		options := &ebiten.DrawImageOptions{}
+
+		// apply modification
+		options.GeoM = sprite.geoM()
+		options.GeoM.Concat(v.geoM)
 
-		// change Hue and Alpha
-		options.ColorM.Scale(sprite.Red, sprite.Green, sprite.Blue, sprite.Alpha)
+		// change Hue, Alpha and the colour matrix
+		options.ColorM = sprite.worldColorM()
+		options.ColorM.Concat(v.colorM)
 
 		// Choose current image inside animation
-		x0 := currentAnimation.CurrentStep * currentAnimation.StepWidth
-		x1 := x0 + currentAnimation.StepWidth
-		r := image.Rect(x0, 0, x1, currentAnimation.StepHeight)
+		r := currentAnimation.stepRect(currentAnimation.CurrentStep)
 		options.SourceRect = &r
 
-		if sprite.Borders {
-			sprite.DrawBorders(surface, violet)
+		// skip the frame when it is outside of the viewport, the children may be inside
+		if v.visible(&options.GeoM, float64(r.Dx()), float64(r.Dy())) {
+			if sprite.Borders {
+				sprite.drawBorders(surface, violet, &v.geoM)
+			}
+
+			if !sprite.blinkHidden {
+				surface.DrawImage(currentAnimation.Image, options)
+			}
 		}
 
-		surface.DrawImage(currentAnimation.Image, options)
+		for _, child := range sprite.children {
+			child.drawView(surface, v)
+		}
+	}
+}
+
+// build the transformation of the current frame : pivot, then zoom, rotation, position and skew of the sprite and its parents
+func (sprite *Sprite) geoM() ebiten.GeoM {
+	var g ebiten.GeoM
+	x, y := sprite.Pivot()
+	g.Translate(-x, -y)
+	g.Concat(sprite.nodeGeoM())
+	return g
+}
+
+/*
+Pivot returns the point of the current step placed at X and Y coordonnates (in pixel from the top-left corner of the step)
+
+It is the pivot of the step if any, else the pivot of the animation, else the anchor of the sprite
+*/
+func (sprite *Sprite) Pivot() (float64, float64) {
+	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	if p, ok := currentAnimation.StepPivots[currentAnimation.CurrentStep]; ok {
+		return p.X, p.Y
+	}
+	if currentAnimation.Pivot != nil {
+		return currentAnimation.Pivot.X, currentAnimation.Pivot.Y
+	}
+	if sprite.CenterCoordonnates {
+		return sprite.GetWidth() / 2, sprite.GetHeight() / 2
+	}
+	return sprite.GetWidth() * sprite.AnchorX, sprite.GetHeight() * sprite.AnchorY
+}
+
+/*
+SetPivot sets the pivot of an animation, or of some steps only (in pixel from the top-left corner of the step)
+
+Example :
+
+mySprite.SetPivot("walk-right", 12, 30)   // feet of the character
+mySprite.SetPivot("attack-right", 10, 30, 3, 4)
+*/
+func (sprite *Sprite) SetPivot(label string, x, y float64, steps ...int) {
+	animation := sprite.Animations[label]
+	if len(steps) == 0 {
+		animation.Pivot = &Point{X: x, Y: y}
+		return
+	}
+	if animation.StepPivots == nil {
+		animation.StepPivots = make(map[int]Point)
+	}
+	for _, step := range steps {
+		animation.StepPivots[step] = Point{X: x, Y: y}
+	}
+}
+
+/*
+Update moves the sprite, applies the effects and goes to the next step of animation
+
+Draw calls it when it was not called since the last Draw, call it yourself to update the sprite without drawing it
+*/
+func (sprite *Sprite) Update() {
+	sprite.processPending()
+	sprite.updated = true
+
+	dt := deltaTime(&sprite.lastUpdate)
+
+	for _, child := range sprite.children {
+		child.Update()
+	}
+
+	if sprite.shown() {
+		// move sprite x,y
+		sprite.integrate(dt)
+
+		// apply diffrents effects
+		sprite.applyEffects()
+
+		// keep the sprite inside its world bounds
+		sprite.applyWorldBounds()
 
 		sprite.NextStep()
 	}
 }
 
-//DrawBorders draw debug borders around the sprite
+//DrawBorders draw debug borders around the sprite and its collision shapes (hitboxes in red)
 func (sprite *Sprite) DrawBorders(surface *ebiten.Image, c color.Color) {
-	var x, y, x1, y1 float64
-	if sprite.CenterCoordonnates {
-		x = math.Round(sprite.X - sprite.GetWidth()/2*sprite.ZoomX)
-		y = math.Round(sprite.Y - sprite.GetHeight()/2*sprite.ZoomY)
+	sprite.drawBorders(surface, c, &ebiten.GeoM{})
+}
 
-	} else {
-		x = sprite.X
-		y = sprite.Y
+// draw the borders transformed by a view
+func (sprite *Sprite) drawBorders(surface *ebiten.Image, c color.Color, g *ebiten.GeoM) {
+	corners := sprite.corners()
+	for i := range corners {
+		p1, p2 := corners[i], corners[(i+1)%len(corners)] // top, right, bottom, left
+		drawLine(surface, p1, p2, c, g)
 	}
-	x1 = math.Round(x + sprite.GetWidth()*sprite.ZoomX)
-	y1 = math.Round(y + sprite.GetHeight()*sprite.ZoomY)
 
-	ebitenutil.DrawLine(surface, x, y, x1, y, c)   // top
-	ebitenutil.DrawLine(surface, x, y1, x1, y1, c) // bottom
-	ebitenutil.DrawLine(surface, x, y, x, y1, c)   // left
-	ebitenutil.DrawLine(surface, x1, y, x1, y1, c) // right
+	// collision shapes of the current step
+	sprite.drawShapes(surface, c, g)
+}
+
+// draw a line between two points transformed by a view
+func drawLine(surface *ebiten.Image, p1, p2 Point, c color.Color, g *ebiten.GeoM) {
+	x1, y1 := g.Apply(p1.X, p1.Y)
+	x2, y2 := g.Apply(p2.X, p2.Y)
+	ebitenutil.DrawLine(surface, math.Round(x1), math.Round(y1), math.Round(x2), math.Round(y2), c)
 }
 
 //Start the animation (Reset+Show+Resume)
@@ -657,11 +1117,23 @@ func (sprite *Sprite) Start() {
 RunOnce start the animation only one time (Reset+Show+Resume)
 
 After running animation, call the callback and pass the sprite pointer as argument
+
+The returned handle is done after the callback
 */
-func (sprite *Sprite) RunOnce(c func(*Sprite)) {
+func (sprite *Sprite) RunOnce(c func(*Sprite)) *Handle {
+	h := newHandle()
+	sprite.runOnce(c, h)
+	return h
+}
+
+func (sprite *Sprite) runOnce(c func(*Sprite), h *Handle) {
 	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
+	if currentAnimation.runOnceHandle != nil { // restarted before the end
+		currentAnimation.runOnceHandle.close()
+	}
 	currentAnimation.RunOnce = true
 	currentAnimation.callbackAfterRunOnce = c
+	currentAnimation.runOnceHandle = h
 	sprite.Reset()
 	sprite.Show()
 	sprite.Resume()
@@ -679,12 +1151,12 @@ func (sprite *Sprite) Reset() {
 	currentAnimation.CurrentStep = currentAnimation.FirstStep
 }
 
-//Pause the animation
+//Pause the animation, the children are paused too but keep their own status
 func (sprite *Sprite) Pause() {
 	sprite.Animated = false
 }
 
-//Resume the animation
+//Resume the animation, the children run again unless they were paused themselves
 func (sprite *Sprite) Resume() {
 	sprite.Animated = true
 }
@@ -705,17 +1177,33 @@ Return true if animation go to the next step or false if step duration is not fi
 */
 func (sprite *Sprite) NextStep() bool {
 	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
-	if sprite.Animated {
+	if sprite.running() {
 		now := time.Now()
-		nextStepAt := currentAnimation.currentStepTimeStart.Add(currentAnimation.OneStepDuration)
-
-		if now.Sub(nextStepAt) > 0 { // time to change the current step
+		if currentAnimation.stepOver(now) { // time to change the current step
 			currentAnimation.CurrentStep++ // next step
 			if currentAnimation.CurrentStep+1 > currentAnimation.Steps {
 				if currentAnimation.RunOnce { // run only one time
 					sprite.Stop()
 					sprite.Hide()
-					currentAnimation.callbackAfterRunOnce(sprite)
+					h := currentAnimation.runOnceHandle // the callback may run it again
+					currentAnimation.runOnceHandle = nil
+					if currentAnimation.callbackAfterRunOnce != nil {
+						currentAnimation.callbackAfterRunOnce(sprite)
+					}
+					if h != nil {
+						h.close()
+					}
+					if !sprite.Visible && sprite.nextQueuedAnimation() { // play the queued animation
+						currentAnimation = sprite.Animations[sprite.CurrentAnimation]
+						sprite.Show()
+						sprite.Resume()
+					}
+					if !sprite.Visible { // not run again by the callback
+						sprite.releaseToPool()
+					}
+
+				} else if sprite.nextQueuedAnimation() {
+					currentAnimation = sprite.Animations[sprite.CurrentAnimation] // play the queued animation
 
 				} else {
 					sprite.Reset() // restart at the end of the animation
@@ -728,29 +1216,56 @@ func (sprite *Sprite) NextStep() bool {
 	return false
 }
 
-func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
+func (sprite *Sprite) applyEffects() {
 	currentAnimation := sprite.Animations[sprite.CurrentAnimation]
 
+	// Shake and Blink effects are computed again at each frame
+	sprite.shakeX, sprite.shakeY = 0, 0
+	sprite.blinkHidden = false
+
 	for _, e := range currentAnimation.Effects { // foreach Effects in the stack
 
 		// if an animation is defined
 		//e := currentAnimation.Effect
-		if e != nil {
+		if e != nil && !e.finished {
+			if e.options.handle != nil && e.options.handle.isCanceled() { // canceled from the context
+				if e.started {
+					sprite.endEffect(e)
+				}
+				e.finished = true
+				e.options.handle.close()
+				continue
+			}
+
 			if e.options.Effect > 0 {
 				// first drawing ? defined the time for first step
 				if e.timeStart.IsZero() || e.timeStart.Unix() == 0 {
-					e.timeStart = time.Now()
+					delay := e.options.Delay
+					if e.options.loopCounter > 1 { // repetition
+						delay = e.options.RepeatDelay
+					}
+					e.timeStart = time.Now().Add(time.Millisecond * time.Duration(delay))
 					e.timeEnd = e.timeStart.Add(e.options.durationTime)
 					//fmt.Printf("Demarre une animation %v\n            et la fin %v\n", e.timeStart, e.timeEnd)
 				}
 
 				now := time.Now()
+				if now.Before(e.timeStart) { // waiting for the delay
+					continue
+				}
+				if !e.started {
+					e.started = true
+					sprite.startEffect(e)
+				}
 				durationFromStart := now.Sub(e.timeStart)
 
 				// animation not finished
 				if e.timeEnd.Sub(now) > 0 {
 
 					where := float64(durationFromStart.Nanoseconds()) / float64(e.options.durationTime.Nanoseconds())
+					if e.options.GoBack && e.options.YoyoCount > 1 { // several go and return in one run
+						where = math.Mod(where*float64(e.options.YoyoCount), 1)
+					}
 					zoomFactor := 1.0
 					//fmt.Printf("Effect:%d\n",e.options.Effect )
 
@@ -759,13 +1274,13 @@ func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
 						if e.options.GoBack { // go and return
 							step := 0.5
 							if where < step {
-								zoomFactor = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.zoomStart, max: e.options.Zoom})
+								zoomFactor = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.zoomStart, max: e.zoomEnd})
 							} else {
-								zoomFactor = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Zoom, max: e.zoomStart})
+								zoomFactor = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.zoomEnd, max: e.zoomStart})
 							}
 
 						} else { // only one way
-							zoomFactor = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.zoomStart, max: e.options.Zoom})
+							zoomFactor = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.zoomStart, max: e.zoomEnd})
 						}
 						sprite.ZoomX = zoomFactor
 						sprite.ZoomY = zoomFactor
@@ -803,32 +1318,27 @@ func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
 						if e.options.GoBack { // go and return
 							step := 0.5
 							if where < step {
-								sprite.Alpha = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.options.FadeFrom, max: e.options.FadeTo})
+								sprite.Alpha = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.alphaFrom, max: e.alphaEnd})
 							} else {
-								sprite.Alpha = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.FadeTo, max: e.options.FadeFrom})
+								sprite.Alpha = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.alphaEnd, max: e.alphaFrom})
 							}
 
 						} else { // only one way
-							sprite.Alpha = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.options.FadeFrom, max: e.options.FadeTo})
+							sprite.Alpha = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.alphaFrom, max: e.alphaEnd})
 						}
 						///////////////////////////////////////////////
 
 					case Turn:
-						clockwise := 1.0
-						if e.options.Clockwise {
-							clockwise = -1.0
-						}
-
 						if e.options.GoBack { // go and return
 							step := 0.5
 							if where < step {
-								sprite.Angle = convertScale(where, &scale{min: 0, max: step}, &scale{min: 0, max: e.options.Angle * clockwise})
+								sprite.Angle = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.angleStart, max: e.angleEnd})
 							} else {
-								sprite.Angle = convertScale(where, &scale{min: step * 1, max: step * 2}, &scale{min: e.options.Angle * clockwise, max: 0})
+								sprite.Angle = convertScale(where, &scale{min: step * 1, max: step * 2}, &scale{min: e.angleEnd, max: e.angleStart})
 							}
 
 						} else {
-							sprite.Angle = convertScale(where, &scale{min: 0, max: 1}, &scale{min: 0, max: e.options.Angle * clockwise})
+							sprite.Angle = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.angleStart, max: e.angleEnd})
 						}
 					///////////////////////////////////////////////
 
@@ -836,19 +1346,19 @@ func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
 						if e.options.GoBack { // go and return
 							step := 0.5
 							if where < step {
-								sprite.Red = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.redStart, max: e.options.Red})
-								sprite.Green = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.greenStart, max: e.options.Green})
-								sprite.Blue = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.blueStart, max: e.options.Blue})
+								sprite.Red = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.redStart, max: e.redEnd})
+								sprite.Green = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.greenStart, max: e.greenEnd})
+								sprite.Blue = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.blueStart, max: e.blueEnd})
 							} else {
-								sprite.Red = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Red, max: e.redStart})
-								sprite.Green = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Green, max: e.greenStart})
-								sprite.Blue = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Blue, max: e.blueStart})
+								sprite.Red = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.redEnd, max: e.redStart})
+								sprite.Green = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.greenEnd, max: e.greenStart})
+								sprite.Blue = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.blueEnd, max: e.blueStart})
 							}
 
 						} else { // only one way
-							sprite.Red = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.redStart, max: e.options.Red})
-							sprite.Green = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.greenStart, max: e.options.Green})
-							sprite.Blue = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.blueStart, max: e.options.Blue})
+							sprite.Red = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.redStart, max: e.redEnd})
+							sprite.Green = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.greenStart, max: e.greenEnd})
+							sprite.Blue = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.blueStart, max: e.blueEnd})
 						}
 						///////////////////////////////////////////////
 
@@ -856,16 +1366,86 @@ func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
 						if e.options.GoBack { // go and return
 							step := 0.5
 							if where < step {
-								sprite.X = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.xStart, max: e.options.X})
-								sprite.Y = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.yStart, max: e.options.Y})
+								sprite.X = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.xStart, max: e.xEnd})
+								sprite.Y = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.yStart, max: e.yEnd})
 							} else {
-								sprite.X = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.X, max: e.xStart})
-								sprite.Y = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Y, max: e.yStart})
+								sprite.X = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.xEnd, max: e.xStart})
+								sprite.Y = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.yEnd, max: e.yStart})
 							}
 
 						} else { // only one way
-							sprite.X = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.xStart, max: e.options.X})
-							sprite.Y = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.yStart, max: e.options.Y})
+							sprite.X = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.xStart, max: e.xEnd})
+							sprite.Y = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.yStart, max: e.yEnd})
+						}
+						///////////////////////////////////////////////
+
+					case Shake:
+						// pick a new random offset at the frequency of the shake
+						if now.After(e.shakeNext) {
+							e.shakeDX = rand.Float64()*2 - 1
+							e.shakeDY = rand.Float64()*2 - 1
+							if e.options.Frequency > 0 {
+								e.shakeNext = now.Add(time.Duration(float64(time.Second) / e.options.Frequency))
+							}
+						}
+						amplitude := e.options.Amplitude * (1 - e.options.Decay*where)
+						sprite.shakeX += e.shakeDX * amplitude
+						sprite.shakeY += e.shakeDY * amplitude
+						///////////////////////////////////////////////
+
+					case Blink:
+						if int(durationFromStart.Seconds()*e.options.Frequency*2)%2 == 1 {
+							sprite.blinkHidden = true
+						}
+						///////////////////////////////////////////////
+
+					case Pulse:
+						wave := (1 - math.Cos(2*math.Pi*e.options.Frequency*durationFromStart.Seconds())) / 2 // from 0 to 1
+						if e.options.PulseAlpha {
+							sprite.Alpha = e.alphaStart * (1 - e.options.Amplitude*wave)
+						} else {
+							sprite.ZoomX = e.zoomStart * (1 + e.options.Amplitude*wave)
+							sprite.ZoomY = e.zoomStartY * (1 + e.options.Amplitude*wave)
+						}
+						///////////////////////////////////////////////
+
+					case FollowPath:
+						if e.options.Path != nil {
+							distance := where * e.options.Path.Length()
+							back := false
+							if e.options.GoBack { // go and return
+								step := 0.5
+								if where < step {
+									distance = convertScale(where, &scale{min: 0, max: step}, &scale{min: 0, max: e.options.Path.Length()})
+								} else {
+									distance = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.options.Path.Length(), max: 0})
+									back = true
+								}
+							}
+
+							position, angle := e.options.Path.At(distance)
+							sprite.X = position.X
+							sprite.Y = position.Y
+							if e.options.OrientToPath {
+								if back {
+									angle += 180
+								}
+								sprite.Angle = angle
+							}
+						}
+						///////////////////////////////////////////////
+
+					case Tint, Saturate, Brighten, Contrast, RotateHue, Invert:
+						if e.options.GoBack { // go and return
+							step := 0.5
+							if where < step {
+								*e.value = convertScale(where, &scale{min: 0, max: step}, &scale{min: e.valueStart, max: e.valueEnd})
+							} else {
+								*e.value = convertScale(where, &scale{min: step, max: 1}, &scale{min: e.valueEnd, max: e.valueStart})
+							}
+
+						} else { // only one way
+							*e.value = convertScale(where, &scale{min: 0, max: 1}, &scale{min: e.valueStart, max: e.valueEnd})
 						}
 						///////////////////////////////////////////////
 
@@ -873,19 +1453,69 @@ func (sprite *Sprite) applyEffects(surface *ebiten.Image) {
 
 					// animation finished
 				} else {
+					sprite.endEffect(e)
+
 					// repeat animation
-					if e.repeatCallback != nil {
+					repeated := false
+					if e.repeatCallback != nil && (e.options.RepeatCount == 0 || e.options.loopCounter <= int64(e.options.RepeatCount)) {
 						e.repeatCallback()
+						repeated = true
+					} else {
+						e.finished = true
 					}
 
 					// laucnh user Callback
 					if e.options.Callback != nil {
 						e.options.Callback()
 					}
+					if repeated && e.options.OnRepeat != nil {
+						e.options.OnRepeat(int(e.options.loopCounter - 1))
+					}
+					if e.finished && e.options.handle != nil {
+						e.options.handle.close()
+					}
 				}
 			} // if e.effect
 		} // if e != nil
 	} // foreach Effect
+	currentAnimation.compactEffects()
+
+	// keyframe tracks
+	sprite.applyTracks()
+}
+
+// remove the finished effects from the stack, the repeated effects keep their new index
+func (animation *Animation) compactEffects() {
+	effects := animation.Effects[:0]
+	for _, e := range animation.Effects {
+		if e != nil && !e.finished {
+			e.options.index = len(effects)
+			effects = append(effects, e)
+		}
+	}
+	for i := len(effects); i < len(animation.Effects); i++ {
+		animation.Effects[i] = nil
+	}
+	animation.Effects = effects
+}
+
+// rectangle of a step inside the image of the animation
+func (animation *Animation) stepRect(step int) image.Rectangle {
+	if step < len(animation.Frames) {
+		return animation.Frames[step]
+	}
+	x0 := step * animation.StepWidth
+	x1 := x0 + animation.StepWidth
+	return image.Rect(x0, 0, x1, animation.StepHeight)
+}
+
+// report whether the duration of the current step is over
+func (animation *Animation) stepOver(now time.Time) bool {
+	duration := animation.OneStepDuration
+	if animation.CurrentStep < len(animation.StepDurations) {
+		duration = animation.StepDurations[animation.CurrentStep]
+	}
+	return now.Sub(animation.currentStepTimeStart.Add(duration)) > 0
 }
 
 //////////////////////////////////////////// TOOLS ////////////////////////////////////////////////:
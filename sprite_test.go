@@ -0,0 +1,68 @@
+package sprite
+
+import (
+	"math"
+	"testing"
+)
+
+// sprite with a default animation of one step of this size and no image
+func newTestSprite(x, y, width, height float64) *Sprite {
+	s := NewSprite()
+	s.Animations["default"] = &Animation{Steps: 1, StepWidth: int(width), StepHeight: int(height)}
+	s.X, s.Y = x, y
+	return s
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func assertNear(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if !near(got, want) {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestAnchorAndPivot(t *testing.T) {
+	// the anchor places the bottom-center of the step at the coordonnates
+	s := newTestSprite(100, 50, 20, 10)
+	s.AnchorX, s.AnchorY = 0.5, 1
+	g := s.geoM()
+	x, y := g.Apply(0, 0)
+	assertNear(t, "anchor left", x, 90)
+	assertNear(t, "anchor top", y, 40)
+	x, y = g.Apply(10, 10)
+	assertNear(t, "anchor x", x, 100)
+	assertNear(t, "anchor y", y, 50)
+
+	// the pivot of the animation is used instead, and stays in place when zooming
+	s.SetPivot("default", 5, 5)
+	s.Zoom(2)
+	g = s.geoM()
+	x, y = g.Apply(5, 5)
+	assertNear(t, "zoomed pivot x", x, 100)
+	assertNear(t, "zoomed pivot y", y, 50)
+	x, y = g.Apply(0, 0)
+	assertNear(t, "zoomed left", x, 90)
+	assertNear(t, "zoomed top", y, 40)
+
+	// and when turning counter-clockwise : a point at the right of the pivot goes up
+	s.Zoom(1)
+	s.Angle = 90
+	g = s.geoM()
+	x, y = g.Apply(5, 5)
+	assertNear(t, "turned pivot x", x, 100)
+	assertNear(t, "turned pivot y", y, 50)
+	x, y = g.Apply(15, 5)
+	assertNear(t, "turned x", x, 100)
+	assertNear(t, "turned y", y, 40)
+
+	// the pivot of the step is used before the one of the animation
+	s.Angle = 0
+	s.SetPivot("default", 0, 10, 0)
+	g = s.geoM()
+	x, y = g.Apply(0, 10)
+	assertNear(t, "step pivot x", x, 100)
+	assertNear(t, "step pivot y", y, 50)
+}
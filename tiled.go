@@ -0,0 +1,850 @@
+package sprite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+// Integer property of the tiles of Tiled holding their collision flags
+const tiledFlagsProperty = "flags"
+
+//Map is a level made with the Tiled editor (https://www.mapeditor.org)
+type Map struct {
+	// Size of the map (in tile)
+	Width, Height int
+
+	// Size of a tile (in pixel)
+	TileWidth, TileHeight int
+
+	// Custom properties of the map
+	Properties map[string]string
+
+	// Tilesets of the map, sorted by first id
+	Tilesets []*Tileset
+
+	// Tile layers, image layers and sprites of the object layers, in the order of drawing
+	Layers []Drawable
+
+	// Tile layers of the map
+	TileLayers []*TileLayer
+
+	// Object layers of the map
+	ObjectLayers []*ObjectLayer
+}
+
+//ObjectLayer is a layer of objects of a map
+type ObjectLayer struct {
+	// Name of the layer
+	Name string
+
+	// Custom properties of the layer
+	Properties map[string]string
+
+	// Objects of the layer
+	Objects []*MapObject
+
+	// Sprites of the tile objects
+	Group *Group
+}
+
+//MapObject is an object of an object layer
+type MapObject struct {
+	// Id, name and type (or class) of the object
+	ID   int
+	Name string
+	Type string
+
+	// Position of the object in the world (bottom-left corner for tile objects, top-left corner for the others)
+	X, Y float64
+
+	// Size of the object (in pixel)
+	Width, Height float64
+
+	// Angle of rotation in degres, around the position
+	Rotation float64
+
+	// Visibility of the object
+	Visible bool
+
+	// Custom properties of the object
+	Properties map[string]string
+
+	// Sprite of a tile object (nil is none)
+	Sprite *Sprite
+
+	// Collision shape in the world of a rectangle, an ellipse or a polygon (nil is none)
+	Shape Shape
+
+	// Points in the world of a polygon or a polyline
+	Points []Point
+}
+
+/*
+LoadMap loads a Tiled map (.tmx or .tmj), its tilesets (.tsx or .tsj) and its images
+
+Tile objects become sprites, animated like their tiles. The other objects become collision shapes.
+Tiles with collision shapes in Tiled get the collision flag 1, or the value of their "flags" integer property.
+The parallax factors of the layers become the ScrollX and ScrollY of the tile layers, the groups of the object layers and the image layers
+
+An error is returned when a file cannot be read or decoded, or uses a feature which is not supported (collections of images, maps which are not orthogonal)
+
+"filter" is ebiten.FilterDefault or ebiten.FilterNearest  or ebiten.FilterLinear
+
+Example :
+
+level, err := sprite.LoadMap("level1.tmx", ebiten.FilterNearest)
+if err != nil {
+	log.Fatal(err)
+}
+camera.Limits = &sprite.Rect{Max: sprite.Point{X: level.PixelWidth(), Y: level.PixelHeight()}}
+camera.Draw(surface, level.Layers...)
+*/
+func LoadMap(path string, filter ebiten.Filter) (*Map, error) {
+	var raw tiledMap
+	if err := readTiled(path, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Orientation != "" && raw.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("sprite: %s: %s maps are not supported", path, raw.Orientation)
+	}
+
+	m := new(Map)
+	m.Width = raw.Width
+	m.Height = raw.Height
+	m.TileWidth = raw.TileWidth
+	m.TileHeight = raw.TileHeight
+	m.Properties = raw.Properties.values()
+
+	dir := filepath.Dir(path)
+	for i := range raw.Tilesets {
+		t := &raw.Tilesets[i]
+		tilesetDir := dir
+		if t.Source != "" { // external tileset
+			firstGID := t.FirstGID
+			source := filepath.Join(dir, t.Source)
+			if err := readTiled(source, t); err != nil {
+				return nil, err
+			}
+			t.FirstGID = firstGID
+			tilesetDir = filepath.Dir(source)
+		}
+		ts, err := newTiledTileset(t, tilesetDir, filter)
+		if err != nil {
+			return nil, err
+		}
+		ts.FirstID = t.FirstGID
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sort.SliceStable(m.Tilesets, func(i, j int) bool { return m.Tilesets[i].FirstID < m.Tilesets[j].FirstID })
+
+	if err := m.addLayers(raw.Layers, dir, filter, &tiledParent{opacity: 1, visible: true, parallaxX: 1, parallaxY: 1}); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+//LoadTileset loads a Tiled tileset (.tsx or .tsj) with its animated tiles, collision flags, collision shapes and properties, see LoadMap for the errors
+func LoadTileset(path string, filter ebiten.Filter) (*Tileset, error) {
+	var raw tiledTileset
+	if err := readTiled(path, &raw); err != nil {
+		return nil, err
+	}
+	return newTiledTileset(&raw, filepath.Dir(path), filter)
+}
+
+//PixelWidth returns the width of the map (in pixel)
+func (m *Map) PixelWidth() float64 {
+	return float64(m.Width * m.TileWidth)
+}
+
+//PixelHeight returns the height of the map (in pixel)
+func (m *Map) PixelHeight() float64 {
+	return float64(m.Height * m.TileHeight)
+}
+
+//TileLayer returns the tile layer with this name, nil if there is none
+func (m *Map) TileLayer(name string) *TileLayer {
+	for _, layer := range m.TileLayers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+//ObjectLayer returns the object layer with this name, nil if there is none
+func (m *Map) ObjectLayer(name string) *ObjectLayer {
+	for _, layer := range m.ObjectLayers {
+		if layer.Name == name {
+			return layer
+		}
+	}
+	return nil
+}
+
+// offset, opacity, visibility and parallax inherited from the groups of layers
+type tiledParent struct {
+	offsetX, offsetY     float64
+	opacity              float64
+	visible              bool
+	parallaxX, parallaxY float64
+}
+
+func (m *Map) addLayers(layers []tiledLayer, dir string, filter ebiten.Filter, parent *tiledParent) error {
+	for i := range layers {
+		raw := &layers[i]
+		p := &tiledParent{
+			offsetX:   parent.offsetX + raw.OffsetX,
+			offsetY:   parent.offsetY + raw.OffsetY,
+			opacity:   parent.opacity * raw.opacity(),
+			visible:   parent.visible && raw.Visible.or(true),
+			parallaxX: parent.parallaxX * raw.ParallaxX.or(1),
+			parallaxY: parent.parallaxY * raw.ParallaxY.or(1),
+		}
+
+		switch raw.kind() {
+		case "tilelayer":
+			layer, err := m.newTileLayer(raw, p)
+			if err != nil {
+				return err
+			}
+			m.TileLayers = append(m.TileLayers, layer)
+			m.Layers = append(m.Layers, layer)
+
+		case "objectgroup":
+			layer := m.newObjectLayer(raw, p)
+			m.ObjectLayers = append(m.ObjectLayers, layer)
+			m.Layers = append(m.Layers, layer.Group)
+
+		case "imagelayer":
+			image := raw.Image
+			if image == "" {
+				image = raw.XMLImage.Source
+			}
+			if image == "" {
+				continue
+			}
+			animation, err := newAnimationFromFile(filepath.Join(dir, image), 0, 1, filter)
+			if err != nil {
+				return fmt.Errorf("sprite: layer %q: %v", raw.Name, err)
+			}
+			layer := NewParallaxLayer(p.parallaxX, p.parallaxY)
+			layer.Animations["default"] = animation
+			layer.Position(p.offsetX, p.offsetY)
+			layer.RepeatX = bool(raw.RepeatX)
+			layer.RepeatY = bool(raw.RepeatY)
+			layer.Alpha = p.opacity
+			layer.Visible = p.visible
+			m.Layers = append(m.Layers, layer)
+
+		case "group":
+			if err := m.addLayers(raw.Layers, dir, filter, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Map) newTileLayer(raw *tiledLayer, p *tiledParent) (*TileLayer, error) {
+	layer := new(TileLayer)
+	layer.Name = raw.Name
+	layer.Tilesets = m.Tilesets
+	layer.TileWidth = m.TileWidth
+	layer.TileHeight = m.TileHeight
+	layer.X = p.offsetX
+	layer.Y = p.offsetY
+	layer.ScrollX = p.parallaxX
+	layer.ScrollY = p.parallaxY
+	layer.Visible = p.visible
+	layer.Red = 1
+	layer.Green = 1
+	layer.Blue = 1
+	layer.Alpha = p.opacity
+	layer.Properties = raw.Properties.values()
+
+	encoding, compression := raw.Encoding, raw.Compression
+	if raw.XMLData.Encoding != "" {
+		encoding, compression = raw.XMLData.Encoding, raw.XMLData.Compression
+	}
+
+	chunks := raw.Chunks
+	if len(raw.XMLData.Chunks) > 0 {
+		chunks = raw.XMLData.Chunks
+	}
+	if len(chunks) == 0 { // finite map : the layer is one chunk
+		chunks = []tiledChunk{{Width: raw.Width, Height: raw.Height, Text: raw.XMLData.Text, Tiles: raw.XMLData.Tiles, Data: raw.Data}}
+	}
+
+	// the grid covers all the chunks of an infinite map
+	minX, minY, maxX, maxY := math.MaxInt32, math.MaxInt32, math.MinInt32, math.MinInt32
+	for _, c := range chunks {
+		minX, minY = minInt(minX, c.X), minInt(minY, c.Y)
+		maxX, maxY = maxInt(maxX, c.X+c.Width), maxInt(maxY, c.Y+c.Height)
+	}
+	layer.Columns = maxX - minX
+	layer.Rows = maxY - minY
+	layer.X += float64(minX * m.TileWidth)
+	layer.Y += float64(minY * m.TileHeight)
+	layer.Tiles = make([]Tile, layer.Columns*layer.Rows)
+
+	for _, c := range chunks {
+		tiles, err := decodeTiles(encoding, compression, &c)
+		if err != nil {
+			return nil, fmt.Errorf("sprite: layer %q: %v", raw.Name, err)
+		}
+		for i, t := range tiles {
+			if i >= c.Width*c.Height {
+				break
+			}
+			layer.SetTile(c.X-minX+i%c.Width, c.Y-minY+i/c.Width, t)
+		}
+	}
+	return layer, nil
+}
+
+func (m *Map) newObjectLayer(raw *tiledLayer, p *tiledParent) *ObjectLayer {
+	layer := new(ObjectLayer)
+	layer.Name = raw.Name
+	layer.Properties = raw.Properties.values()
+	layer.Group = NewGroup()
+	layer.Group.Alpha = p.opacity
+	layer.Group.Visible = p.visible
+	layer.Group.ScrollX = p.parallaxX
+	layer.Group.ScrollY = p.parallaxY
+
+	for i := range raw.Objects {
+		o := &raw.Objects[i]
+		object := &MapObject{
+			ID:         o.ID,
+			Name:       o.Name,
+			Type:       o.Type,
+			X:          o.X + p.offsetX,
+			Y:          o.Y + p.offsetY,
+			Width:      o.Width,
+			Height:     o.Height,
+			Rotation:   o.Rotation,
+			Visible:    o.Visible.or(true),
+			Properties: o.Properties.values(),
+		}
+		if object.Type == "" {
+			object.Type = o.Class
+		}
+
+		if o.GID != 0 {
+			object.Sprite = m.newTileSprite(object, Tile(o.GID))
+			if object.Sprite != nil {
+				layer.Group.Add(object.Sprite)
+			}
+		} else {
+			object.Shape, object.Points = o.shape(object.X, object.Y)
+		}
+		layer.Objects = append(layer.Objects, object)
+	}
+	return layer
+}
+
+// sprite of a tile object, animated like its tile
+func (m *Map) newTileSprite(object *MapObject, t Tile) *Sprite {
+	layer := TileLayer{Tilesets: m.Tilesets}
+	ts, index := layer.tileset(t)
+	if ts == nil {
+		return nil
+	}
+
+	var animation *Animation
+	if a, ok := ts.Animations[index]; ok { // a copy, each sprite has its own current step
+		animation = new(Animation)
+		*animation = *a
+		animation.CurrentStep = 0
+		animation.currentStepTimeStart = time.Now()
+	} else {
+		animation = ts.newAnimation([]int{index}, []time.Duration{0})
+	}
+	animation.Effects = make([]*animationEffect, 0)
+	animation.Tracks = make([]*animationTrack, 0)
+
+	s := NewSprite()
+	s.Animations["default"] = animation
+	for _, shape := range ts.Shapes[index] {
+		s.AddShape("default", ShapeHurtbox, shape)
+	}
+
+	// the position is the bottom-left corner, a flipped tile stays in place
+	s.AnchorX, s.AnchorY = 0, 1
+	s.Position(object.X, object.Y)
+	s.Angle = -object.Rotation // clockwise in Tiled
+	if object.Width > 0 {
+		s.ZoomX = object.Width / float64(ts.TileWidth)
+	}
+	if object.Height > 0 {
+		s.ZoomY = object.Height / float64(ts.TileHeight)
+	}
+	if t&TileFlipX != 0 {
+		s.AnchorX = 1
+		s.ZoomX = -s.ZoomX
+	}
+	if t&TileFlipY != 0 {
+		s.AnchorY = 0
+		s.ZoomY = -s.ZoomY
+	}
+	s.Visible = object.Visible
+	return s
+}
+
+func newTiledTileset(raw *tiledTileset, dir string, filter ebiten.Filter) (*Tileset, error) {
+	source := raw.Image
+	if source == "" {
+		source = raw.XMLImage.Source
+	}
+	if source == "" {
+		return nil, fmt.Errorf("sprite: tileset %q: collections of images are not supported", raw.Name)
+	}
+
+	ts, err := newTilesetFromFile(filepath.Join(dir, source), raw.TileWidth, raw.TileHeight, raw.Margin, raw.Spacing, filter)
+	if err != nil {
+		return nil, fmt.Errorf("sprite: tileset %q: %v", raw.Name, err)
+	}
+	ts.Name = raw.Name
+	if raw.Columns > 0 {
+		ts.Columns = raw.Columns
+	}
+	if raw.TileCount > 0 {
+		ts.Count = raw.TileCount
+	}
+	ts.Properties = raw.Properties.values()
+
+	for i := range raw.Tiles {
+		tile := &raw.Tiles[i]
+		ts.TileProperties[tile.ID] = tile.Properties.values()
+
+		frames := tile.Animation
+		if len(frames) == 0 {
+			frames = tile.XMLAnimation.Frames
+		}
+		if len(frames) > 0 {
+			indexes := make([]int, len(frames))
+			durations := make([]time.Duration, len(frames))
+			for j, f := range frames {
+				indexes[j] = f.TileID
+				durations[j] = time.Millisecond * time.Duration(f.Duration)
+			}
+			ts.Animations[tile.ID] = ts.newAnimation(indexes, durations)
+		}
+
+		if tile.ObjectGroup != nil {
+			for j := range tile.ObjectGroup.Objects {
+				if shape, _ := tile.ObjectGroup.Objects[j].shape(tile.ObjectGroup.Objects[j].X, tile.ObjectGroup.Objects[j].Y); shape != nil {
+					ts.Shapes[tile.ID] = append(ts.Shapes[tile.ID], shape)
+				}
+			}
+			if len(ts.Shapes[tile.ID]) > 0 {
+				ts.Flags[tile.ID] = 1
+			}
+		}
+
+		if value, ok := ts.TileProperties[tile.ID][tiledFlagsProperty]; ok {
+			flags, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("sprite: tileset %q: tile %d: %v", raw.Name, tile.ID, err)
+			}
+			ts.Flags[tile.ID] = uint32(flags)
+		}
+	}
+	return ts, nil
+}
+
+// decode a file in XML or in JSON from its extension
+func readTiled(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmj", ".tsj", ".json":
+		err = json.Unmarshal(data, v)
+	default:
+		err = xml.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("sprite: %s: %v", path, err)
+	}
+	return nil
+}
+
+// decode the tiles of a chunk : csv, base64 (uncompressed, gzip or zlib), XML elements or JSON array
+func decodeTiles(encoding, compression string, c *tiledChunk) ([]Tile, error) {
+	text := c.Text
+	if len(c.Data) > 0 {
+		if c.Data[0] != '"' { // JSON array
+			var tiles []Tile
+			err := json.Unmarshal(c.Data, &tiles)
+			return tiles, err
+		}
+		if err := json.Unmarshal(c.Data, &text); err != nil {
+			return nil, err
+		}
+	}
+
+	switch encoding {
+	case "":
+		tiles := make([]Tile, len(c.Tiles))
+		for i, t := range c.Tiles {
+			tiles[i] = Tile(t.GID)
+		}
+		return tiles, nil
+
+	case "csv":
+		var tiles []Tile
+		for _, field := range strings.Split(text, ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			tiles = append(tiles, Tile(gid))
+		}
+		return tiles, nil
+
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, err
+		}
+		switch compression {
+		case "":
+		case "gzip", "zlib":
+			if data, err = decompress(compression, data); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%s compression is not supported", compression)
+		}
+		tiles := make([]Tile, len(data)/4)
+		for i := range tiles {
+			tiles[i] = Tile(binary.LittleEndian.Uint32(data[i*4:]))
+		}
+		return tiles, nil
+	}
+	return nil, fmt.Errorf("%s encoding is not supported", encoding)
+}
+
+func decompress(compression string, data []byte) ([]byte, error) {
+	var r io.ReadCloser
+	var err error
+	if compression == "gzip" {
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	} else {
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// collision shape and points in the world of an object which is not a tile, at a position
+func (o *tiledObject) shape(x, y float64) (Shape, []Point) {
+	// points around the position, before the rotation
+	var points []Point
+	switch {
+	case len(o.Polygon) > 0:
+		points = o.Polygon
+	case len(o.Polyline) > 0:
+		points = o.Polyline
+	case bool(o.Point):
+		return nil, []Point{{X: x, Y: y}}
+	case bool(o.Ellipse):
+		if o.Width == o.Height && o.Rotation == 0 {
+			return Circle{X: x + o.Width/2, Y: y + o.Height/2, Radius: o.Width / 2}, nil
+		}
+		for i := 0; i < circleResolution; i++ {
+			a := 2 * math.Pi * float64(i) / circleResolution
+			points = append(points, Point{X: o.Width / 2 * (1 + math.Cos(a)), Y: o.Height / 2 * (1 + math.Sin(a))})
+		}
+	default:
+		if o.Width == 0 && o.Height == 0 {
+			return nil, []Point{{X: x, Y: y}}
+		}
+		points = []Point{{}, {X: o.Width}, {X: o.Width, Y: o.Height}, {Y: o.Height}}
+	}
+
+	var g ebiten.GeoM
+	g.Rotate(o.Rotation * math.Pi / 180) // clockwise in Tiled, like the Y axis going down
+	g.Translate(x, y)
+	world := make([]Point, len(points))
+	for i, p := range points {
+		world[i].X, world[i].Y = g.Apply(p.X, p.Y)
+	}
+
+	if len(o.Polyline) > 0 {
+		return nil, world
+	}
+	return Polygon{Points: world}, world
+}
+
+//////////////////////////////////////////// TILED FILES ////////////////////////////////////////////
+
+type tiledMap struct {
+	Orientation string          `xml:"orientation,attr" json:"orientation"`
+	Width       int             `xml:"width,attr" json:"width"`
+	Height      int             `xml:"height,attr" json:"height"`
+	TileWidth   int             `xml:"tilewidth,attr" json:"tilewidth"`
+	TileHeight  int             `xml:"tileheight,attr" json:"tileheight"`
+	Properties  tiledProperties `xml:"properties" json:"properties"`
+	Tilesets    []tiledTileset  `xml:"tileset" json:"tilesets"`
+	Layers      []tiledLayer    `xml:",any" json:"layers"`
+}
+
+type tiledTileset struct {
+	FirstGID   int             `xml:"firstgid,attr" json:"firstgid"`
+	Source     string          `xml:"source,attr" json:"source"`
+	Name       string          `xml:"name,attr" json:"name"`
+	TileWidth  int             `xml:"tilewidth,attr" json:"tilewidth"`
+	TileHeight int             `xml:"tileheight,attr" json:"tileheight"`
+	Margin     int             `xml:"margin,attr" json:"margin"`
+	Spacing    int             `xml:"spacing,attr" json:"spacing"`
+	TileCount  int             `xml:"tilecount,attr" json:"tilecount"`
+	Columns    int             `xml:"columns,attr" json:"columns"`
+	Image      string          `xml:"-" json:"image"`
+	XMLImage   tiledImage      `xml:"image" json:"-"`
+	Properties tiledProperties `xml:"properties" json:"properties"`
+	Tiles      []tiledTile     `xml:"tile" json:"tiles"`
+}
+
+type tiledImage struct {
+	Source string `xml:"source,attr"`
+}
+
+type tiledTile struct {
+	ID           int               `xml:"id,attr" json:"id"`
+	Properties   tiledProperties   `xml:"properties" json:"properties"`
+	ObjectGroup  *tiledObjectGroup `xml:"objectgroup" json:"objectgroup"`
+	Animation    []tiledFrame      `xml:"-" json:"animation"`
+	XMLAnimation struct {
+		Frames []tiledFrame `xml:"frame"`
+	} `xml:"animation" json:"-"`
+}
+
+type tiledFrame struct {
+	TileID   int `xml:"tileid,attr" json:"tileid"`
+	Duration int `xml:"duration,attr" json:"duration"`
+}
+
+type tiledObjectGroup struct {
+	Objects []tiledObject `xml:"object" json:"objects"`
+}
+
+type tiledLayer struct {
+	XMLName    xml.Name        `json:"-"`
+	Type       string          `xml:"-" json:"type"`
+	Name       string          `xml:"name,attr" json:"name"`
+	Width      int             `xml:"width,attr" json:"width"`
+	Height     int             `xml:"height,attr" json:"height"`
+	Visible    *tiledBool      `xml:"visible,attr" json:"visible"`
+	Opacity    *float64        `xml:"opacity,attr" json:"opacity"`
+	OffsetX    float64         `xml:"offsetx,attr" json:"offsetx"`
+	OffsetY    float64         `xml:"offsety,attr" json:"offsety"`
+	ParallaxX  *tiledFloat     `xml:"parallaxx,attr" json:"parallaxx"`
+	ParallaxY  *tiledFloat     `xml:"parallaxy,attr" json:"parallaxy"`
+	RepeatX    tiledBool       `xml:"repeatx,attr" json:"repeatx"`
+	RepeatY    tiledBool       `xml:"repeaty,attr" json:"repeaty"`
+	Properties tiledProperties `xml:"properties" json:"properties"`
+
+	// tile layer
+	Data        json.RawMessage `xml:"-" json:"data"`
+	Chunks      []tiledChunk    `xml:"-" json:"chunks"`
+	Encoding    string          `xml:"-" json:"encoding"`
+	Compression string          `xml:"-" json:"compression"`
+	XMLData     tiledData       `xml:"data" json:"-"`
+
+	// object layer
+	Objects []tiledObject `xml:"object" json:"objects"`
+
+	// image layer
+	Image    string     `xml:"-" json:"image"`
+	XMLImage tiledImage `xml:"image" json:"-"`
+
+	// group of layers
+	Layers []tiledLayer `xml:",any" json:"layers"`
+}
+
+// type of the layer, from its XML element in a .tmx file
+func (l *tiledLayer) kind() string {
+	switch l.XMLName.Local {
+	case "layer":
+		return "tilelayer"
+	case "":
+		return l.Type
+	}
+	return l.XMLName.Local
+}
+
+func (l *tiledLayer) opacity() float64 {
+	if l.Opacity == nil {
+		return 1
+	}
+	return *l.Opacity
+}
+
+type tiledData struct {
+	Encoding    string       `xml:"encoding,attr"`
+	Compression string       `xml:"compression,attr"`
+	Text        string       `xml:",chardata"`
+	Tiles       []tiledGID   `xml:"tile"`
+	Chunks      []tiledChunk `xml:"chunk"`
+}
+
+type tiledGID struct {
+	GID uint32 `xml:"gid,attr"`
+}
+
+type tiledChunk struct {
+	X      int             `xml:"x,attr" json:"x"`
+	Y      int             `xml:"y,attr" json:"y"`
+	Width  int             `xml:"width,attr" json:"width"`
+	Height int             `xml:"height,attr" json:"height"`
+	Text   string          `xml:",chardata" json:"-"`
+	Tiles  []tiledGID      `xml:"tile" json:"-"`
+	Data   json.RawMessage `xml:"-" json:"data"`
+}
+
+type tiledObject struct {
+	ID         int             `xml:"id,attr" json:"id"`
+	Name       string          `xml:"name,attr" json:"name"`
+	Type       string          `xml:"type,attr" json:"type"`
+	Class      string          `xml:"class,attr" json:"class"`
+	X          float64         `xml:"x,attr" json:"x"`
+	Y          float64         `xml:"y,attr" json:"y"`
+	Width      float64         `xml:"width,attr" json:"width"`
+	Height     float64         `xml:"height,attr" json:"height"`
+	Rotation   float64         `xml:"rotation,attr" json:"rotation"`
+	GID        uint32          `xml:"gid,attr" json:"gid"`
+	Visible    *tiledBool      `xml:"visible,attr" json:"visible"`
+	Properties tiledProperties `xml:"properties" json:"properties"`
+	Ellipse    tiledFlag       `xml:"ellipse" json:"ellipse"`
+	Point      tiledFlag       `xml:"point" json:"point"`
+	Polygon    tiledPoints     `xml:"polygon" json:"polygon"`
+	Polyline   tiledPoints     `xml:"polyline" json:"polyline"`
+}
+
+// boolean written 0 or 1 in .tmx files, and true or false in .tmj files
+type tiledBool bool
+
+func (b *tiledBool) UnmarshalXMLAttr(attr xml.Attr) error {
+	*b = attr.Value == "1" || attr.Value == "true"
+	return nil
+}
+
+func (b *tiledBool) UnmarshalJSON(data []byte) error {
+	*b = string(data) == "1" || string(data) == "true"
+	return nil
+}
+
+func (b *tiledBool) or(def bool) bool {
+	if b == nil {
+		return def
+	}
+	return bool(*b)
+}
+
+type tiledFloat float64
+
+func (f *tiledFloat) or(def float64) float64 {
+	if f == nil {
+		return def
+	}
+	return float64(*f)
+}
+
+// empty element in .tmx files, boolean in .tmj files
+type tiledFlag bool
+
+func (f *tiledFlag) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*f = true
+	return d.Skip()
+}
+
+// "x,y x,y" in .tmx files, array of {x, y} in .tmj files
+type tiledPoints []Point
+
+func (p *tiledPoints) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Points string `xml:"points,attr"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	for _, pair := range strings.Fields(raw.Points) {
+		var point Point
+		if _, err := fmt.Sscanf(pair, "%g,%g", &point.X, &point.Y); err != nil {
+			return err
+		}
+		*p = append(*p, point)
+	}
+	return nil
+}
+
+func (p *tiledPoints) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*[]Point)(p))
+}
+
+// custom properties, converted to strings
+type tiledProperties []tiledProperty
+
+type tiledProperty struct {
+	Name  string      `xml:"name,attr" json:"name"`
+	Value interface{} `xml:"-" json:"value"`
+	Attr  string      `xml:"value,attr" json:"-"`
+	Text  string      `xml:",chardata" json:"-"`
+}
+
+func (p *tiledProperties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw struct {
+		Properties []tiledProperty `xml:"property"`
+	}
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+	*p = append(*p, raw.Properties...)
+	return nil
+}
+
+func (p tiledProperties) values() map[string]string {
+	values := make(map[string]string, len(p))
+	for _, property := range p {
+		switch value := property.Value.(type) {
+		case float64: // JSON numbers, without exponent
+			values[property.Name] = strconv.FormatFloat(value, 'f', -1, 64)
+		case nil:
+			if property.Attr != "" {
+				values[property.Name] = property.Attr
+			} else { // multiline strings
+				values[property.Name] = property.Text
+			}
+		default:
+			values[property.Name] = fmt.Sprint(value)
+		}
+	}
+	return values
+}
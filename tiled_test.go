@@ -0,0 +1,154 @@
+package sprite
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten"
+)
+
+const testTiledMap = `<map orientation="orthogonal" width="3" height="2" tilewidth="10" tileheight="20">
+ <group name="back" parallaxx="0.5" offsetx="5">
+  <layer name="ground" width="3" height="2" parallaxx="0.5">
+   <data encoding="csv">1,2147483650,0,1073741827,3221225473,536870916</data>
+  </layer>
+ </group>
+ <objectgroup name="things" parallaxy="2">
+  <object id="1" name="plank" x="100" y="50" width="20" height="10" rotation="90"/>
+  <object id="2" gid="2" x="0" y="100" width="10" height="20" rotation="90"/>
+  <object id="3" gid="2147483650" x="50" y="100" width="10" height="20"/>
+ </objectgroup>
+</map>`
+
+// map decoded from testTiledMap, with a tileset without image
+func decodeTestMap(t *testing.T) *Map {
+	var raw tiledMap
+	if err := xml.Unmarshal([]byte(testTiledMap), &raw); err != nil {
+		t.Fatal(err)
+	}
+	ts := &Tileset{
+		TileWidth: 10, TileHeight: 20, Columns: 4, Count: 8, FirstID: 1,
+		Animations: make(map[int]*Animation), Flags: make(map[int]uint32), Shapes: make(map[int][]Shape),
+	}
+	m := &Map{Width: raw.Width, Height: raw.Height, TileWidth: raw.TileWidth, TileHeight: raw.TileHeight, Tilesets: []*Tileset{ts}}
+	if err := m.addLayers(raw.Layers, "", ebiten.FilterDefault, &tiledParent{opacity: 1, visible: true, parallaxX: 1, parallaxY: 1}); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestTiledTileLayer(t *testing.T) {
+	m := decodeTestMap(t)
+	layer := m.TileLayer("ground")
+	if layer == nil {
+		t.Fatal("no ground layer")
+	}
+	assertNear(t, "x", layer.X, 5)
+	assertNear(t, "scroll x", layer.ScrollX, 0.25)
+	assertNear(t, "scroll y", layer.ScrollY, 1)
+
+	tests := []struct {
+		column, row int
+		id          int
+		flags       Tile
+	}{
+		{0, 0, 1, 0},
+		{1, 0, 2, TileFlipX},
+		{2, 0, 0, 0},
+		{0, 1, 3, TileFlipY},
+		{1, 1, 1, TileFlipX | TileFlipY},
+		{2, 1, 4, TileFlipDiagonal},
+	}
+	for _, test := range tests {
+		tile := layer.TileAt(test.column, test.row)
+		if tile.ID() != test.id || tile&tileFlags != test.flags {
+			t.Errorf("tile %d,%d : id %d flags %x, want %d %x", test.column, test.row, tile.ID(), tile&tileFlags, test.id, test.flags)
+		}
+	}
+}
+
+func TestTiledRotatedObjects(t *testing.T) {
+	m := decodeTestMap(t)
+	layer := m.ObjectLayer("things")
+	if layer == nil || len(layer.Objects) != 3 {
+		t.Fatal("wrong objects")
+	}
+	assertNear(t, "group scroll y", layer.Group.ScrollY, 2)
+
+	// turned clockwise around its top-left corner
+	plank, ok := layer.Objects[0].Shape.(Polygon)
+	if !ok {
+		t.Fatal("the rectangle is not a polygon")
+	}
+	want := []Point{{X: 100, Y: 50}, {X: 100, Y: 70}, {X: 90, Y: 70}, {X: 90, Y: 50}}
+	for i, p := range want {
+		assertNear(t, "plank x", plank.Points[i].X, p.X)
+		assertNear(t, "plank y", plank.Points[i].Y, p.Y)
+	}
+
+	// turned clockwise around its bottom-left corner : the top-left corner goes to the right
+	s := layer.Objects[1].Sprite
+	if s == nil {
+		t.Fatal("no sprite for the tile object")
+	}
+	assertNear(t, "angle", s.Angle, -90)
+	c := s.corners()
+	assertNear(t, "top-left x", c[0].X, 20)
+	assertNear(t, "top-left y", c[0].Y, 100)
+
+	// flipped tile object, in place
+	flipped := layer.Objects[2].Sprite
+	if flipped.ZoomX >= 0 {
+		t.Error("the flipped tile object is not flipped")
+	}
+	b := flipped.Bounds()
+	assertNear(t, "flipped min x", b.Min.X, 50)
+	assertNear(t, "flipped min y", b.Min.Y, 80)
+}
+
+func TestTiledErrors(t *testing.T) {
+	if _, err := newTiledTileset(&tiledTileset{Name: "props"}, "", ebiten.FilterDefault); err == nil {
+		t.Error("no error for a collection of images")
+	}
+
+	var raw tiledMap
+	if err := xml.Unmarshal([]byte(`<map><layer name="bad" width="2" height="1"><data encoding="csv">1,x</data></layer></map>`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := new(Map).addLayers(raw.Layers, "", ebiten.FilterDefault, &tiledParent{opacity: 1, visible: true}); err == nil {
+		t.Error("no error for bad tiles")
+	}
+
+	if _, err := LoadMap("missing.tmx", ebiten.FilterDefault); err == nil {
+		t.Error("no error for a missing map")
+	}
+}
+
+func TestTiledMissingImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tiled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	maps := map[string]string{
+		"tileset": `<map width="1" height="1" tilewidth="16" tileheight="16">
+ <tileset firstgid="1" name="ground" tilewidth="16" tileheight="16" margin="1" spacing="2"><image source="missing.png"/></tileset>
+</map>`,
+		"image layer": `<map width="1" height="1" tilewidth="16" tileheight="16">
+ <imagelayer name="sky"><image source="missing.png"/></imagelayer>
+</map>`,
+	}
+	for name, data := range maps {
+		path := filepath.Join(dir, "map.tmx")
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if m, err := LoadMap(path, ebiten.FilterDefault); err == nil || m != nil {
+			t.Errorf("%s : no error for a missing image", name)
+		}
+	}
+}
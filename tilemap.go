@@ -0,0 +1,463 @@
+package sprite
+
+import (
+	"image"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/hajimehoshi/ebiten"
+	"github.com/hajimehoshi/ebiten/ebitenutil"
+)
+
+//Tile is the id of a tile in the tilesets of a layer (0 is an empty cell), combined with the flip flags
+type Tile uint32
+
+// Flip flags of the tiles, the diagonal flip is applied first
+const (
+	TileFlipX        Tile = 1 << 31
+	TileFlipY        Tile = 1 << 30
+	TileFlipDiagonal Tile = 1 << 29
+
+	tileFlags = TileFlipX | TileFlipY | TileFlipDiagonal
+)
+
+//ID returns the id of the tile without the flip flags
+func (t Tile) ID() int {
+	return int(t &^ tileFlags)
+}
+
+//Tileset is an image sliced into tiles, like the steps of an animation on several lines
+type Tileset struct {
+	// Name of the tileset
+	Name string
+
+	// ebiten.Image of the tiles
+	Image *ebiten.Image
+
+	// Size of the tiles (in pixel)
+	TileWidth, TileHeight int
+
+	// Space around the tiles and between the tiles in the image (in pixel)
+	Margin, Spacing int
+
+	// Number of tiles on one line of the image, and in the whole image
+	Columns, Count int
+
+	// Id of the first tile of the tileset in the layers (default is 1)
+	FirstID int
+
+	// Animations of the animated tiles, by index of the tile in the tileset
+	Animations map[int]*Animation
+
+	// Collision flags of the tiles (bit field), by index of the tile in the tileset
+	Flags map[int]uint32
+
+	// Collision shapes of the tiles (in pixel from the top-left corner of the tile), by index of the tile in the tileset
+	Shapes map[int][]Shape
+
+	// Custom properties of the tileset, and of the tiles by index of the tile in the tileset
+	Properties     map[string]string
+	TileProperties map[int]map[string]string
+}
+
+/*
+NewTileset loads a tileset, tiles are read from left to right and from top to bottom
+
+"filter" is ebiten.FilterDefault or ebiten.FilterNearest  or ebiten.FilterLinear
+
+Example :
+
+tileset := sprite.NewTileset("tiles.png", 16, 16, ebiten.FilterNearest)
+*/
+func NewTileset(path string, tileWidth, tileHeight int, filter ebiten.Filter) *Tileset {
+	ts, err := newTilesetFromFile(path, tileWidth, tileHeight, 0, 0, filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return ts
+}
+
+func newTilesetFromFile(path string, tileWidth, tileHeight, margin, spacing int, filter ebiten.Filter) (*Tileset, error) {
+	img, _, err := ebitenutil.NewImageFromFile(path, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newTileset(img, tileWidth, tileHeight, margin, spacing), nil
+}
+
+func newTileset(img *ebiten.Image, tileWidth, tileHeight, margin, spacing int) *Tileset {
+	ts := new(Tileset)
+	ts.Image = img
+	ts.TileWidth = tileWidth
+	ts.TileHeight = tileHeight
+	ts.Margin = margin
+	ts.Spacing = spacing
+	ts.FirstID = 1
+	ts.Animations = make(map[int]*Animation)
+	ts.Flags = make(map[int]uint32)
+	ts.Shapes = make(map[int][]Shape)
+	ts.Properties = make(map[string]string)
+	ts.TileProperties = make(map[int]map[string]string)
+
+	width, height := img.Size()
+	ts.Columns = (width - 2*margin + spacing) / (tileWidth + spacing)
+	ts.Count = ts.Columns * ((height - 2*margin + spacing) / (tileHeight + spacing))
+	return ts
+}
+
+//Tile returns the tile of the layers for an index of the tileset
+func (ts *Tileset) Tile(index int) Tile {
+	return Tile(ts.FirstID + index)
+}
+
+// rectangle of a tile inside the image of the tileset
+func (ts *Tileset) tileRect(index int) image.Rectangle {
+	x := ts.Margin + (index%ts.Columns)*(ts.TileWidth+ts.Spacing)
+	y := ts.Margin + (index/ts.Columns)*(ts.TileHeight+ts.Spacing)
+	return image.Rect(x, y, x+ts.TileWidth, y+ts.TileHeight)
+}
+
+/*
+AddAnimatedTile animates a tile with other tiles of the tileset, played in loop
+
+"duration" is in millisecond, for the whole animation
+
+Example :
+
+tileset.AddAnimatedTile(12, 800, 12, 13, 14, 15) // water
+*/
+func (ts *Tileset) AddAnimatedTile(index int, duration int, frames ...int) *Animation {
+	durations := make([]time.Duration, len(frames))
+	for i := range durations {
+		durations[i] = time.Millisecond * time.Duration(duration) / time.Duration(len(frames))
+	}
+	animation := ts.newAnimation(frames, durations)
+	ts.Animations[index] = animation
+	return animation
+}
+
+// build an animation from tiles of the tileset
+func (ts *Tileset) newAnimation(frames []int, durations []time.Duration) *Animation {
+	animation := new(Animation)
+	animation.Image = ts.Image
+	animation.Steps = len(frames)
+	animation.StepWidth = ts.TileWidth
+	animation.StepHeight = ts.TileHeight
+	animation.StepDurations = durations
+	for i, frame := range frames {
+		animation.Frames = append(animation.Frames, ts.tileRect(frame))
+		animation.Duration += durations[i]
+	}
+	animation.currentStepTimeStart = time.Now()
+	return animation
+}
+
+/*
+SetFlags sets the collision flags (bit field) of tiles of the tileset
+
+Example :
+
+const solid = 1
+tileset.SetFlags(solid, 0, 1, 2, 8)
+*/
+func (ts *Tileset) SetFlags(flags uint32, indexes ...int) {
+	for _, index := range indexes {
+		ts.Flags[index] = flags
+	}
+}
+
+// advance the animated tiles
+func (ts *Tileset) update(now time.Time) {
+	for _, animation := range ts.Animations {
+		if animation.Steps > 0 && animation.stepOver(now) {
+			animation.CurrentStep = (animation.CurrentStep + 1) % animation.Steps
+			animation.currentStepTimeStart = now
+		}
+	}
+}
+
+//TileLayer is a grid of tiles
+type TileLayer struct {
+	// Name of the layer
+	Name string
+
+	// Tilesets of the tiles, sorted by first id
+	Tilesets []*Tileset
+
+	// Size of the grid (in cell)
+	Columns, Rows int
+
+	// Size of a cell (in pixel), tiles larger than a cell are aligned to the bottom-left corner
+	TileWidth, TileHeight int
+
+	// Tiles of the cells from left to right and from top to bottom
+	Tiles []Tile
+
+	// Position of the top-left corner of the layer in the world
+	X, Y float64
+
+	// Part of the movement of the camera followed by the layer (0 is fixed on the screen, 1 moves with the world)
+	ScrollX, ScrollY float64
+
+	// Order of drawing inside a group
+	Z float64
+
+	// Custom properties of the layer
+	Properties map[string]string
+
+	// Visibility of the layer
+	Visible bool
+
+	// Colors multipliers
+	Red, Green, Blue float64
+
+	// Transparency
+	Alpha float64
+
+	// Update was called since the last Draw
+	updated bool
+}
+
+//TileCell is a cell of a tile layer
+type TileCell struct {
+	// Column and row of the cell
+	Column, Row int
+
+	// Tile of the cell
+	Tile Tile
+
+	// Rectangle of the cell in the world
+	Rect Rect
+
+	// Collision flags of the tile
+	Flags uint32
+}
+
+/*
+NewTileLayer creates an empty grid of tiles of the size of the tiles of the tileset
+
+Example :
+
+ground := sprite.NewTileLayer(tileset, 40, 30)
+ground.SetTile(0, 0, tileset.Tile(3)|sprite.TileFlipX)
+*/
+func NewTileLayer(tileset *Tileset, columns, rows int) *TileLayer {
+	layer := new(TileLayer)
+	layer.Tilesets = []*Tileset{tileset}
+	layer.Columns = columns
+	layer.Rows = rows
+	layer.TileWidth = tileset.TileWidth
+	layer.TileHeight = tileset.TileHeight
+	layer.Tiles = make([]Tile, columns*rows)
+	layer.Properties = make(map[string]string)
+	layer.ScrollX = 1
+	layer.ScrollY = 1
+	layer.Visible = true
+	layer.Red = 1
+	layer.Green = 1
+	layer.Blue = 1
+	layer.Alpha = 1
+	return layer
+}
+
+//SetTile sets the tile of a cell, cells outside of the grid are ignored
+func (layer *TileLayer) SetTile(column, row int, t Tile) {
+	if column >= 0 && row >= 0 && column < layer.Columns && row < layer.Rows {
+		layer.Tiles[row*layer.Columns+column] = t
+	}
+}
+
+//TileAt returns the tile of a cell, cells outside of the grid are empty
+func (layer *TileLayer) TileAt(column, row int) Tile {
+	if column >= 0 && row >= 0 && column < layer.Columns && row < layer.Rows {
+		return layer.Tiles[row*layer.Columns+column]
+	}
+	return 0
+}
+
+//CellAt returns the column and the row of the cell at a position of the world
+func (layer *TileLayer) CellAt(x, y float64) (int, int) {
+	column := int(math.Floor((x - layer.X) / float64(layer.TileWidth)))
+	row := int(math.Floor((y - layer.Y) / float64(layer.TileHeight)))
+	return column, row
+}
+
+// tileset of a tile and the index of the tile in it, nil if there is none
+func (layer *TileLayer) tileset(t Tile) (*Tileset, int) {
+	id := t.ID()
+	if id == 0 {
+		return nil, 0
+	}
+	i := sort.Search(len(layer.Tilesets), func(i int) bool { return layer.Tilesets[i].FirstID > id }) - 1
+	if i < 0 {
+		return nil, 0
+	}
+	return layer.Tilesets[i], id - layer.Tilesets[i].FirstID
+}
+
+//FlagsAt returns the collision flags of the tile at a position of the world
+func (layer *TileLayer) FlagsAt(x, y float64) uint32 {
+	ts, index := layer.tileset(layer.TileAt(layer.CellAt(x, y)))
+	if ts == nil {
+		return 0
+	}
+	return ts.Flags[index]
+}
+
+/*
+CellsInRect returns the cells overlapping a rectangle of the world with at least one of the collision flags
+
+Example :
+
+for _, cell := range ground.CellsInRect(hero.Bounds(), solid) {
+	// push the hero out of cell.Rect
+}
+*/
+func (layer *TileLayer) CellsInRect(r Rect, flags uint32) []TileCell {
+	minColumn, minRow := layer.CellAt(r.Min.X, r.Min.Y)
+	maxColumn, maxRow := layer.CellAt(r.Max.X, r.Max.Y)
+
+	var cells []TileCell
+	for row := maxInt(minRow, 0); row <= minInt(maxRow, layer.Rows-1); row++ {
+		for column := maxInt(minColumn, 0); column <= minInt(maxColumn, layer.Columns-1); column++ {
+			t := layer.Tiles[row*layer.Columns+column]
+			ts, index := layer.tileset(t)
+			if ts == nil || ts.Flags[index]&flags == 0 {
+				continue
+			}
+			cell := TileCell{Column: column, Row: row, Tile: t, Flags: ts.Flags[index]}
+			cell.Rect = NewRect(layer.X+float64(column*layer.TileWidth), layer.Y+float64(row*layer.TileHeight), float64(layer.TileWidth), float64(layer.TileHeight))
+			if cell.Rect.Overlaps(r) {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+//Overlaps reports whether the bounds of the sprite overlap a tile with a flag of its collision mask
+func (layer *TileLayer) Overlaps(s *Sprite) bool {
+	return len(layer.CellsInRect(s.Bounds(), s.CollisionMask)) > 0
+}
+
+//Update advances the animated tiles
+func (layer *TileLayer) Update() {
+	layer.updated = true
+	now := time.Now()
+	for _, ts := range layer.Tilesets {
+		ts.update(now)
+	}
+}
+
+//Draw draws the visible tiles of the layer, after updating it if Update was not called since the last Draw
+func (layer *TileLayer) Draw(surface *ebiten.Image) {
+	layer.prepare()
+	layer.drawView(surface, &view{})
+}
+
+func (layer *TileLayer) prepare() {
+	if !layer.updated {
+		layer.Update()
+	}
+}
+
+func (layer *TileLayer) order() (float64, float64) {
+	return layer.Z, layer.Y
+}
+
+// draw the tiles inside the surface
+func (layer *TileLayer) drawView(surface *ebiten.Image, v *view) {
+	layer.updated = false
+	if !layer.Visible || v.hidden || layer.TileWidth <= 0 || layer.TileHeight <= 0 {
+		return
+	}
+
+	var g ebiten.GeoM
+	g.Translate(layer.X, layer.Y)
+	if v.camera != nil {
+		g.Translate((1-layer.ScrollX)*v.camera.X, (1-layer.ScrollY)*v.camera.Y)
+	}
+	g.Concat(v.geoM)
+	if !g.IsInvertible() {
+		return
+	}
+
+	// area of the surface in the coordonnates of the layer
+	inverse := g
+	inverse.Invert()
+	var corners [4]Point
+	w, h := surface.Size()
+	for i, p := range [4]Point{{}, {X: float64(w)}, {X: float64(w), Y: float64(h)}, {Y: float64(h)}} {
+		corners[i].X, corners[i].Y = inverse.Apply(p.X, p.Y)
+	}
+	area := boundingRect(corners[:])
+
+	// tiles larger than a cell go over the cells on their right and above them
+	extraColumns, extraRows := 0, 0
+	for _, ts := range layer.Tilesets {
+		extraColumns = maxInt(extraColumns, (ts.TileWidth-1)/layer.TileWidth)
+		extraRows = maxInt(extraRows, (ts.TileHeight-1)/layer.TileHeight)
+	}
+	minColumn := maxInt(int(math.Floor(area.Min.X/float64(layer.TileWidth)))-extraColumns, 0)
+	maxColumn := minInt(int(math.Floor(area.Max.X/float64(layer.TileWidth))), layer.Columns-1)
+	minRow := maxInt(int(math.Floor(area.Min.Y/float64(layer.TileHeight))), 0)
+	maxRow := minInt(int(math.Floor(area.Max.Y/float64(layer.TileHeight)))+extraRows, layer.Rows-1)
+	inside := minColumn <= maxColumn && minRow <= maxRow
+	v.count(inside)
+	if !inside {
+		return
+	}
+
+	var colorM ebiten.ColorM
+	colorM.Scale(layer.Red, layer.Green, layer.Blue, layer.Alpha)
+	colorM.Concat(v.colorM)
+
+	for row := minRow; row <= maxRow; row++ {
+		for column := minColumn; column <= maxColumn; column++ {
+			t := layer.Tiles[row*layer.Columns+column]
+			ts, index := layer.tileset(t)
+			if ts == nil {
+				continue
+			}
+
+			r := ts.tileRect(index)
+			if animation, ok := ts.Animations[index]; ok {
+				r = animation.stepRect(animation.CurrentStep)
+			}
+
+			height := r.Dy()
+			if t&TileFlipDiagonal != 0 {
+				height = r.Dx()
+			}
+
+			options := &ebiten.DrawImageOptions{}
+			options.GeoM = tileGeoM(t, float64(r.Dx()), float64(r.Dy()))
+			options.GeoM.Translate(float64(column*layer.TileWidth), float64((row+1)*layer.TileHeight-height))
+			options.GeoM.Concat(g)
+			options.ColorM = colorM
+			options.SourceRect = &r
+			surface.DrawImage(ts.Image, options)
+		}
+	}
+}
+
+// flip a tile of this size inside its rectangle : diagonal flip first, then horizontal and vertical flips
+func tileGeoM(t Tile, width, height float64) ebiten.GeoM {
+	var g ebiten.GeoM
+	if t&TileFlipDiagonal != 0 {
+		g.Rotate(math.Pi / 2) // exchange X and Y
+		g.Scale(-1, 1)
+		width, height = height, width
+	}
+	if t&TileFlipX != 0 {
+		g.Scale(-1, 1)
+		g.Translate(width, 0)
+	}
+	if t&TileFlipY != 0 {
+		g.Scale(1, -1)
+		g.Translate(0, height)
+	}
+	return g
+}
@@ -0,0 +1,112 @@
+package sprite
+
+import (
+	"testing"
+)
+
+const (
+	testSolid uint32 = 1 << iota
+	testWater
+)
+
+// layer of 4 columns and 3 rows at (100, 50), with two tilesets of 16 pixels tiles and no image
+//
+//	S . W .
+//	. S . .
+//	S S . W
+func newTestTileLayer() *TileLayer {
+	ground := &Tileset{TileWidth: 16, TileHeight: 16, FirstID: 1, Flags: map[int]uint32{0: testSolid}}
+	water := &Tileset{TileWidth: 16, TileHeight: 16, FirstID: 10, Flags: map[int]uint32{2: testWater}}
+	layer := NewTileLayer(ground, 4, 3)
+	layer.Tilesets = append(layer.Tilesets, water)
+	layer.X, layer.Y = 100, 50
+	layer.SetTile(0, 0, ground.Tile(0)|TileFlipX)
+	layer.SetTile(2, 0, water.Tile(2))
+	layer.SetTile(1, 1, ground.Tile(0))
+	layer.SetTile(3, 1, ground.Tile(1)) // no flags
+	layer.SetTile(0, 2, ground.Tile(0))
+	layer.SetTile(1, 2, ground.Tile(0)|TileFlipDiagonal)
+	layer.SetTile(3, 2, water.Tile(2))
+	return layer
+}
+
+func TestTileFlagsAt(t *testing.T) {
+	layer := newTestTileLayer()
+	tests := []struct {
+		x, y float64
+		want uint32
+	}{
+		{100, 50, testSolid}, // flipped tile
+		{115.9, 65.9, testSolid},
+		{116, 50, 0},         // empty cell
+		{140, 60, testWater}, // second tileset
+		{160, 70, 0},         // tile without flags
+		{130, 90, testSolid}, // diagonal flip
+		{99, 50, 0},          // outside of the grid
+		{150, 98, 0},         // past the last row
+	}
+	for _, test := range tests {
+		if got := layer.FlagsAt(test.x, test.y); got != test.want {
+			t.Errorf("FlagsAt(%v, %v) = %d, want %d", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestTileCellsInRect(t *testing.T) {
+	layer := newTestTileLayer()
+	tests := []struct {
+		name  string
+		r     Rect
+		flags uint32
+		want  []TileCell
+	}{
+		{"whole layer", NewRect(100, 50, 64, 48), testSolid, []TileCell{{Column: 0, Row: 0}, {Column: 1, Row: 1}, {Column: 0, Row: 2}, {Column: 1, Row: 2}}},
+		{"both flags", NewRect(100, 50, 64, 48), testSolid | testWater, []TileCell{{Column: 0, Row: 0}, {Column: 2, Row: 0}, {Column: 1, Row: 1}, {Column: 0, Row: 2}, {Column: 1, Row: 2}, {Column: 3, Row: 2}}},
+		{"larger than the layer", NewRect(0, 0, 500, 500), testWater, []TileCell{{Column: 2, Row: 0}, {Column: 3, Row: 2}}},
+		{"ending on the edge of a cell", NewRect(90, 40, 26, 26), testSolid, []TileCell{{Column: 0, Row: 0}}},
+		{"one pixel further", NewRect(90, 40, 27, 27), testSolid, []TileCell{{Column: 0, Row: 0}, {Column: 1, Row: 1}}},
+		{"outside", NewRect(0, 0, 100, 50), testSolid, nil},
+		{"no flags", NewRect(100, 50, 64, 48), 0, nil},
+	}
+	for _, test := range tests {
+		cells := layer.CellsInRect(test.r, test.flags)
+		if len(cells) != len(test.want) {
+			t.Errorf("%s : %d cells, want %d", test.name, len(cells), len(test.want))
+			continue
+		}
+		for i, c := range cells {
+			want := test.want[i]
+			if c.Column != want.Column || c.Row != want.Row {
+				t.Errorf("%s : cell %d at %d %d, want %d %d", test.name, i, c.Column, c.Row, want.Column, want.Row)
+			}
+			if c.Tile != layer.TileAt(c.Column, c.Row) || c.Flags&test.flags == 0 {
+				t.Errorf("%s : cell %d has tile %x and flags %d", test.name, i, c.Tile, c.Flags)
+			}
+			assertNear(t, test.name+" cell x", c.Rect.Min.X, 100+float64(c.Column*16))
+			assertNear(t, test.name+" cell y", c.Rect.Min.Y, 50+float64(c.Row*16))
+		}
+	}
+}
+
+func TestTileGeoM(t *testing.T) {
+	// point (1, 2) of a tile 16 pixels wide and 8 pixels high
+	tests := []struct {
+		flags        Tile
+		wantX, wantY float64
+	}{
+		{0, 1, 2},
+		{TileFlipX, 15, 2},
+		{TileFlipY, 1, 6},
+		{TileFlipDiagonal, 2, 1}, // X and Y exchanged : 8 pixels wide and 16 pixels high
+		{TileFlipDiagonal | TileFlipX, 6, 1},
+		{TileFlipDiagonal | TileFlipY, 2, 15},
+		{TileFlipDiagonal | TileFlipX | TileFlipY, 6, 15},
+	}
+	for _, test := range tests {
+		g := tileGeoM(test.flags, 16, 8)
+		x, y := g.Apply(1, 2)
+		if !near(x, test.wantX) || !near(y, test.wantY) {
+			t.Errorf("flags %x : (%v, %v), want (%v, %v)", uint32(test.flags), x, y, test.wantX, test.wantY)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package sprite
+
+import (
+	"sort"
+)
+
+//LayerAll is a collision mask to interact with all the layers
+const LayerAll = ^uint32(0)
+
+//CollisionWorld detects the collisions between its sprites and calls the callbacks
+type CollisionWorld struct {
+	// function to launch when two sprites start to collide
+	OnCollisionEnter func(a, b *Sprite)
+
+	// function to launch at each update while two sprites collide
+	OnCollisionStay func(a, b *Sprite)
+
+	// function to launch when two sprites stop to collide
+	OnCollisionExit func(a, b *Sprite)
+
+	index    SpatialIndex
+	ids      map[*Sprite]int
+	nextID   int
+	contacts map[contact]bool
+}
+
+// two colliding sprites, the first one added to the world first
+type contact struct {
+	a, b *Sprite
+}
+
+/*
+NewCollisionWorld creates a collision world using a spatial index (a spatial hash grid of 64 pixels if nil)
+
+Example :
+
+const (
+	player = 1 << iota
+	enemy
+	bullet
+)
+
+world := sprite.NewCollisionWorld(nil)
+world.OnCollisionEnter = func(a, b *sprite.Sprite) { print("hit\n") }
+hero.CollisionLayer, hero.CollisionMask = player, enemy
+world.Add(hero)
+*/
+func NewCollisionWorld(index SpatialIndex) *CollisionWorld {
+	if index == nil {
+		index = NewSpatialHash(64)
+	}
+	w := new(CollisionWorld)
+	w.index = index
+	w.ids = make(map[*Sprite]int)
+	w.contacts = make(map[contact]bool)
+	return w
+}
+
+//Add adds a sprite to the world
+func (w *CollisionWorld) Add(s *Sprite) {
+	if _, ok := w.ids[s]; ok {
+		return
+	}
+	w.ids[s] = w.nextID
+	w.nextID++
+	w.index.Insert(s)
+}
+
+//Remove removes a sprite from the world, the exit callback is called for its collisions
+func (w *CollisionWorld) Remove(s *Sprite) {
+	if _, ok := w.ids[s]; !ok {
+		return
+	}
+	for _, c := range w.sortedContacts() {
+		if c.a == s || c.b == s {
+			delete(w.contacts, c)
+			if w.OnCollisionExit != nil {
+				w.OnCollisionExit(c.a, c.b)
+			}
+		}
+	}
+	w.index.Remove(s)
+	delete(w.ids, s)
+}
+
+// the layers of the sprites interact
+func interact(a, b *Sprite) bool {
+	return a.CollisionLayer&b.CollisionMask != 0 || b.CollisionLayer&a.CollisionMask != 0
+}
+
+//Update detects the collisions of the visible sprites and calls the callbacks, call it once per frame after moving the sprites (the callbacks can remove sprites)
+func (w *CollisionWorld) Update() {
+	w.index.UpdateAll()
+
+	current := make(map[contact]bool)
+	w.index.Pairs(func(a, b *Sprite) {
+		if !a.shown() || !b.shown() || !interact(a, b) || !a.Overlaps(b) {
+			return
+		}
+		if w.ids[b] < w.ids[a] {
+			a, b = b, a
+		}
+		current[contact{a: a, b: b}] = true
+	})
+
+	// the new contacts are added as they enter, so that a callback removing a sprite only ends the announced ones
+	previous := w.contacts
+	w.contacts = make(map[contact]bool)
+	for c := range current {
+		if previous[c] {
+			w.contacts[c] = true
+		}
+	}
+
+	// a callback may remove sprites : they get no more callbacks
+	for _, c := range sortContacts(w.ids, previous) {
+		if !current[c] && w.has(c) && w.OnCollisionExit != nil {
+			w.OnCollisionExit(c.a, c.b)
+		}
+	}
+	for _, c := range sortContacts(w.ids, current) {
+		if !w.has(c) {
+			continue
+		}
+		if previous[c] {
+			if w.contacts[c] && w.OnCollisionStay != nil {
+				w.OnCollisionStay(c.a, c.b)
+			}
+			continue
+		}
+		w.contacts[c] = true
+		if w.OnCollisionEnter != nil {
+			w.OnCollisionEnter(c.a, c.b)
+		}
+	}
+}
+
+// both sprites of the contact are still in the world
+func (w *CollisionWorld) has(c contact) bool {
+	_, a := w.ids[c.a]
+	_, b := w.ids[c.b]
+	return a && b
+}
+
+//Colliding reports whether the two sprites collided at the last update
+func (w *CollisionWorld) Colliding(a, b *Sprite) bool {
+	return w.contacts[contact{a: a, b: b}] || w.contacts[contact{a: b, b: a}]
+}
+
+func (w *CollisionWorld) sortedContacts() []contact {
+	return sortContacts(w.ids, w.contacts)
+}
+
+// contacts in the order the sprites were added, for repeatable callbacks
+func sortContacts(ids map[*Sprite]int, contacts map[contact]bool) []contact {
+	sorted := make([]contact, 0, len(contacts))
+	for c := range contacts {
+		sorted = append(sorted, c)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if ids[sorted[i].a] != ids[sorted[j].a] {
+			return ids[sorted[i].a] < ids[sorted[j].a]
+		}
+		return ids[sorted[i].b] < ids[sorted[j].b]
+	})
+	return sorted
+}
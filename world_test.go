@@ -0,0 +1,144 @@
+package sprite
+
+import (
+	"testing"
+)
+
+func TestCollisionWorldCallbacks(t *testing.T) {
+	world := NewCollisionWorld(nil)
+	var events []string
+	world.OnCollisionEnter = func(a, b *Sprite) { events = append(events, "enter") }
+	world.OnCollisionStay = func(a, b *Sprite) { events = append(events, "stay") }
+	world.OnCollisionExit = func(a, b *Sprite) { events = append(events, "exit") }
+
+	a := newTestSprite(0, 0, 10, 10)
+	b := newTestSprite(5, 5, 10, 10)
+	a.CollisionLayer, a.CollisionMask = 1, 2
+	b.CollisionLayer, b.CollisionMask = 2, 0
+	world.Add(a)
+	world.Add(b)
+
+	world.Update()
+	world.Update()
+	if !world.Colliding(b, a) {
+		t.Error("the sprites are not colliding")
+	}
+	b.Position(50, 50)
+	world.Update()
+
+	want := []string{"enter", "stay", "exit"}
+	if len(events) != len(want) {
+		t.Fatalf("events %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events %v, want %v", events, want)
+		}
+	}
+
+	// removing a colliding sprite ends its collisions
+	events = nil
+	b.Position(5, 5)
+	world.Update()
+	world.Remove(b)
+	if len(events) != 2 || events[1] != "exit" {
+		t.Errorf("events %v after the removal", events)
+	}
+}
+
+func TestCollisionWorldFilter(t *testing.T) {
+	world := NewCollisionWorld(NewQuadtree(NewRect(0, 0, 256, 256)))
+	count := 0
+	world.OnCollisionEnter = func(a, b *Sprite) { count++ }
+
+	player := newTestSprite(0, 0, 10, 10)
+	enemy := newTestSprite(5, 0, 10, 10)
+	friend := newTestSprite(0, 5, 10, 10)
+	ghost := newTestSprite(5, 5, 10, 10)
+	player.CollisionLayer, player.CollisionMask = 1, 2
+	enemy.CollisionLayer, enemy.CollisionMask = 2, 1
+	friend.CollisionLayer, friend.CollisionMask = 1, 2
+	ghost.CollisionLayer, ghost.CollisionMask = 2, 1
+	ghost.Hide()
+	for _, s := range []*Sprite{player, enemy, friend, ghost} {
+		world.Add(s)
+	}
+
+	world.Update()
+	if count != 2 {
+		t.Errorf("%d collisions, want 2", count)
+	}
+	if world.Colliding(player, friend) {
+		t.Error("the same layers collide")
+	}
+	if world.Colliding(player, ghost) {
+		t.Error("a hidden sprite collides")
+	}
+}
+
+func TestCollisionWorldShapesPastFrame(t *testing.T) {
+	for name, index := range spatialIndexes() {
+		world := NewCollisionWorld(index)
+		count := 0
+		world.OnCollisionEnter = func(a, b *Sprite) { count++ }
+
+		// the sword goes 60 pixels past the frame, farther than a cell
+		knight := newTestSprite(0, 0, 10, 10)
+		knight.Collision = CollisionShapes
+		knight.AddShape("default", ShapeHurtbox, Capsule{A: Point{X: 5, Y: 5}, B: Point{X: 70, Y: 5}, Radius: 2})
+		target := newTestSprite(65, 0, 10, 10)
+		target.Collision = CollisionShapes
+		target.AddShape("default", ShapeHurtbox, Circle{X: 5, Y: 5, Radius: 3})
+		knight.CollisionLayer, knight.CollisionMask = 1, 1
+		target.CollisionLayer, target.CollisionMask = 1, 1
+		world.Add(knight)
+		world.Add(target)
+
+		world.Update()
+		if count != 1 {
+			t.Errorf("%s : the shape past the frame is not tested", name)
+		}
+	}
+}
+
+func TestCollisionWorldRemoveInCallback(t *testing.T) {
+	world := NewCollisionWorld(nil)
+	a := newTestSprite(0, 0, 10, 10)
+	b := newTestSprite(2, 2, 10, 10)
+	c := newTestSprite(4, 4, 10, 10)
+	names := map[*Sprite]string{a: "a", b: "b", c: "c"}
+	for _, s := range []*Sprite{a, b, c} {
+		s.CollisionLayer, s.CollisionMask = 1, 1
+		world.Add(s)
+	}
+
+	var events []string
+	world.OnCollisionEnter = func(x, y *Sprite) {
+		events = append(events, "enter "+names[x]+names[y])
+		if y == b {
+			world.Remove(b) // the bullet hits : it disappears
+		}
+	}
+	world.OnCollisionExit = func(x, y *Sprite) { events = append(events, "exit "+names[x]+names[y]) }
+	world.Update()
+
+	want := []string{"enter ab", "exit ab", "enter ac"}
+	if len(events) != len(want) {
+		t.Fatalf("events %v, want %v", events, want)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("events %v, want %v", events, want)
+		}
+	}
+	if world.Colliding(b, c) {
+		t.Error("the removed sprite still collides")
+	}
+
+	// no exit for the removed sprite at the next update
+	events = nil
+	world.Update()
+	if len(events) != 0 {
+		t.Errorf("events %v at the next update", events)
+	}
+}